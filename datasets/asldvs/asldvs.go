@@ -0,0 +1,93 @@
+package asldvs
+
+import (
+	"errors"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/datasets"
+	"github.com/ffardo/go-event-vision/format/matlab"
+)
+
+const (
+	// Width of the DAVIS240c sensor
+	Width = 240
+	// Height of the DAVIS240c sensor
+	Height = 180
+)
+
+// ClassNames lists the class folders of the dataset, the letters of the American Sign Language alphabet
+// except j and z, which require motion
+var ClassNames = []string{
+	"a", "b", "c", "d", "e", "f", "g", "h", "i", "k", "l", "m",
+	"n", "o", "p", "q", "r", "s", "t", "u", "v", "w", "x", "y",
+}
+
+// AslDvs implements DatasetReader interface for ASL-DVS samples.
+// Samples are MAT-files holding the x, y, ts and pol event arrays. The y axis is stored bottom-up, so it is
+// flipped on read, as done by the reference loaders.
+type AslDvs struct {
+	FilePath string
+}
+
+// Read event capture for an entry in the dataset
+func (a AslDvs) Read() (event.EventCapture, error) {
+	vars, err := matlab.ReadFile(a.FilePath)
+	if err != nil {
+		return event.EventCapture{}, err
+	}
+
+	columns := make([][]int, 4)
+	for i, name := range []string{"x", "y", "ts", "pol"} {
+		v, ok := vars[name]
+		if !ok {
+			return event.EventCapture{}, errors.New("Missing " + name + " array")
+		}
+		columns[i] = v.Ints()
+	}
+
+	n := len(columns[0])
+	for _, c := range columns[1:] {
+		if len(c) != n {
+			return event.EventCapture{}, errors.New("Event arrays have different sizes")
+		}
+	}
+
+	ev := make([]event.Event, n)
+	for i := range ev {
+		ev[i] = event.Event{
+			Coords: event.Point2D{X: columns[0][i], Y: Height - 1 - columns[1][i]},
+			Ts:     columns[2][i],
+			P:      columns[3][i],
+		}
+	}
+
+	return event.EventCapture{Events: ev, Width: Width, Height: Height}, nil
+}
+
+// Write capture to a dataset. Should be used only for data augmentation.
+func (a AslDvs) Write(evCap event.EventCapture) error {
+	n := len(evCap.Events)
+	vars := map[string]matlab.Variable{}
+	for _, name := range []string{"x", "y", "ts", "pol"} {
+		vars[name] = matlab.Variable{Dims: []int{n, 1}, Data: make([]float64, n)}
+	}
+
+	for i, ev := range evCap.Events {
+		vars["x"].Data[i] = float64(ev.Coords.X)
+		vars["y"].Data[i] = float64(Height - 1 - ev.Coords.Y)
+		vars["ts"].Data[i] = float64(ev.Ts)
+		vars["pol"].Data[i] = float64(ev.P)
+	}
+
+	return matlab.WriteFile(a.FilePath, vars)
+}
+
+// Index enumerates the samples of ASL-DVS. root must contain one folder per letter, named as in ClassNames.
+// The dataset has no official split, so Index.StratifiedSplit or Index.KFold can be used to build one.
+func Index(root string) (datasets.Index, error) {
+	samples, err := datasets.Discover(root, ".mat")
+	if err != nil {
+		return datasets.Index{}, err
+	}
+	return datasets.NewIndex(root, samples), nil
+}
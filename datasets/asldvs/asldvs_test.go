@@ -0,0 +1,69 @@
+package asldvs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/format/matlab"
+)
+
+func TestAslDvs_Read(t *testing.T) {
+	dir := t.TempDir()
+	a := AslDvs{FilePath: filepath.Join(dir, "a_0001.mat")}
+
+	evCap := event.EventCapture{
+		Events: []event.Event{
+			{Coords: event.Point2D{X: 10, Y: 30}, Ts: 937, P: 1},
+			{Coords: event.Point2D{X: 239, Y: 179}, Ts: 1030, P: 0},
+			{Coords: event.Point2D{X: 0, Y: 0}, Ts: 1 << 40, P: 1},
+		},
+		Width:  Width,
+		Height: Height,
+	}
+
+	if err := a.Write(evCap); err != nil {
+		t.Fatalf("AslDvs.Write() error = %v", err)
+	}
+
+	got, err := a.Read()
+	if err != nil {
+		t.Fatalf("AslDvs.Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, evCap) {
+		t.Errorf("AslDvs.Read() = %v, want %v", got, evCap)
+	}
+
+	vars, err := matlab.ReadFile(a.FilePath)
+	if err != nil || !reflect.DeepEqual(vars["y"].Data, []float64{149, 0, 179}) {
+		t.Errorf("Stored y = %v, error = %v", vars["y"].Data, err)
+	}
+
+	missing := filepath.Join(dir, "b_0001.mat")
+	if err := matlab.WriteFile(missing, map[string]matlab.Variable{"x": {Dims: []int{1, 1}, Data: []float64{1}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (AslDvs{FilePath: missing}).Read(); err == nil {
+		t.Errorf("AslDvs.Read() without event arrays should return an error")
+	}
+}
+
+func TestIndex(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"a/a_0001.mat", "a/a_0002.mat", "y/y_0001.mat"} {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	idx, err := Index(root)
+	if err != nil || len(idx.Samples) != 3 || !reflect.DeepEqual(idx.Classes, []string{"a", "y"}) {
+		t.Errorf("Index() = %v, error = %v", idx, err)
+	}
+}
@@ -0,0 +1,279 @@
+/*
+automotive implements support for the Prophesee Gen1 Automotive and 1Mpx automotive detection datasets.
+
+Each recording is a pair of files: the events, in a _td.dat file, and the bounding boxes, in a _bbox.npy file
+holding a structured array with ts, x, y, w, h, class_id, confidence and track_id fields. Boxes sharing a
+timestamp form an annotation of the scene at that time.
+*/
+package automotive
+
+import (
+	"errors"
+	"image"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/datasets"
+	"github.com/ffardo/go-event-vision/format/numpy"
+	"github.com/ffardo/go-event-vision/format/prophesee"
+)
+
+const (
+	// Gen1Width is the width of the ATIS sensor used to record Gen1 Automotive
+	Gen1Width = 304
+	// Gen1Height is the height of the ATIS sensor used to record Gen1 Automotive
+	Gen1Height = 240
+
+	// Mpx1Width is the width of the Prophesee Gen4 sensor used to record 1Mpx
+	Mpx1Width = 1280
+	// Mpx1Height is the height of the Prophesee Gen4 sensor used to record 1Mpx
+	Mpx1Height = 720
+
+	eventsSuffix = "_td.dat"
+	boxesSuffix  = "_bbox.npy"
+)
+
+// Gen1ClassNames lists the Gen1 Automotive classes, where Gen1ClassNames[i] is the name of class_id i
+var Gen1ClassNames = []string{"car", "pedestrian"}
+
+// Mpx1ClassNames lists the 1Mpx classes, where Mpx1ClassNames[i] is the name of class_id i
+var Mpx1ClassNames = []string{"pedestrian", "two wheeler", "car", "truck", "bus", "traffic sign", "traffic light"}
+
+// BBox is an annotated bounding box. X and Y are the coordinates of the top left corner.
+type BBox struct {
+	Ts         int
+	X, Y       float64
+	W, H       float64
+	ClassID    int
+	Confidence float64
+	TrackID    int
+}
+
+// Rect returns the box in pixel coordinates, rounded to the nearest pixel
+func (b BBox) Rect() image.Rectangle {
+	return image.Rect(int(math.Round(b.X)), int(math.Round(b.Y)), int(math.Round(b.X+b.W)), int(math.Round(b.Y+b.H)))
+}
+
+// Recording implements DatasetReader interface for a recording of Gen1 Automotive or 1Mpx
+type Recording struct {
+	FilePath  string // path of the _td.dat file
+	BoxesPath string // path of the boxes file. Defaults to the recording path with the _bbox.npy suffix
+	Width     int    // sensor width, such as Gen1Width. Inferred from events when zero
+	Height    int    // sensor height, such as Gen1Height. Inferred from events when zero
+}
+
+// Sample is a time window of a recording ending at an annotation
+type Sample struct {
+	Start   int                // window start timestamp, inclusive
+	End     int                // window end timestamp, exclusive
+	Capture event.EventCapture // events of the window
+	Boxes   []BBox             // boxes annotated at End - 1, the last timestamp of the window
+}
+
+// Read event capture for a whole recording
+func (r Recording) Read() (event.EventCapture, error) {
+	evCap, err := prophesee.Dat{FilePath: r.FilePath}.ReadEvents()
+	if err != nil {
+		return event.EventCapture{}, err
+	}
+
+	if r.Width > 0 && r.Height > 0 {
+		evCap.Width = r.Width
+		evCap.Height = r.Height
+	}
+	return evCap, nil
+}
+
+// Write capture to a dataset. Not supported, as the DAT format is read only.
+func (r Recording) Write(evCap event.EventCapture) error {
+	return prophesee.Dat{FilePath: r.FilePath}.WriteEvents(evCap)
+}
+
+func (r Recording) boxesPath() string {
+	if r.BoxesPath != "" {
+		return r.BoxesPath
+	}
+	return strings.TrimSuffix(r.FilePath, eventsSuffix) + boxesSuffix
+}
+
+// Boxes reads the bounding boxes of the recording
+func (r Recording) Boxes() ([]BBox, error) {
+	return ReadBoxes(r.boxesPath())
+}
+
+// ReadBoxes reads bounding boxes from a _bbox.npy file, sorted by timestamp. Older files, which name the
+// confidence field class_confidence, are supported.
+func ReadBoxes(path string) ([]BBox, error) {
+	a, err := numpy.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ints := map[string][]int64{}
+	for _, name := range []string{"ts", "class_id", "track_id"} {
+		if ints[name], err = a.Ints(name); err != nil {
+			return nil, err
+		}
+	}
+
+	floats := map[string][]float64{}
+	for _, name := range []string{"x", "y", "w", "h"} {
+		if floats[name], err = a.Floats(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if floats["confidence"], err = a.Floats("confidence"); err != nil {
+		if floats["confidence"], err = a.Floats("class_confidence"); err != nil {
+			return nil, errors.New("Missing confidence field")
+		}
+	}
+
+	boxes := make([]BBox, a.Len())
+	for i := range boxes {
+		boxes[i] = BBox{
+			Ts:         int(ints["ts"][i]),
+			X:          floats["x"][i],
+			Y:          floats["y"][i],
+			W:          floats["w"][i],
+			H:          floats["h"][i],
+			ClassID:    int(ints["class_id"][i]),
+			Confidence: floats["confidence"][i],
+			TrackID:    int(ints["track_id"][i]),
+		}
+	}
+
+	sort.SliceStable(boxes, func(i, j int) bool { return boxes[i].Ts < boxes[j].Ts })
+	return boxes, nil
+}
+
+// WriteBoxes writes bounding boxes to a _bbox.npy file, using the field types of the datasets
+func WriteBoxes(path string, boxes []BBox) error {
+	a, err := numpy.NewArray([]numpy.Field{
+		{Name: "ts", Descr: "<u8"},
+		{Name: "x", Descr: "<f4"},
+		{Name: "y", Descr: "<f4"},
+		{Name: "w", Descr: "<f4"},
+		{Name: "h", Descr: "<f4"},
+		{Name: "class_id", Descr: "|u1"},
+		{Name: "confidence", Descr: "<f4"},
+		{Name: "track_id", Descr: "<u4"},
+	}, len(boxes))
+	if err != nil {
+		return err
+	}
+
+	for i, b := range boxes {
+		a.SetInt("ts", i, int64(b.Ts))
+		a.SetFloat("x", i, b.X)
+		a.SetFloat("y", i, b.Y)
+		a.SetFloat("w", i, b.W)
+		a.SetFloat("h", i, b.H)
+		a.SetInt("class_id", i, int64(b.ClassID))
+		a.SetFloat("confidence", i, b.Confidence)
+		a.SetInt("track_id", i, int64(b.TrackID))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if err := a.Write(f); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Window returns the boxes with timestamps in [start, end), which must be sorted by timestamp
+func Window(boxes []BBox, start, end int) []BBox {
+	first := sort.Search(len(boxes), func(i int) bool { return boxes[i].Ts >= start })
+	last := sort.Search(len(boxes), func(i int) bool { return boxes[i].Ts >= end })
+	return boxes[first:last]
+}
+
+// SampleIterator iterates over the labeled samples of a recording, one per annotation timestamp
+type SampleIterator struct {
+	evCap    event.EventCapture
+	boxes    []BBox
+	windowUs int
+	next     int
+	sample   Sample
+}
+
+// Samples returns an iterator over the labeled samples of the recording. Each sample holds the events of the
+// windowUs microsseconds before an annotation timestamp and the boxes annotated at that timestamp.
+// Events are expected to be sorted by timestamp.
+func (r Recording) Samples(windowUs int) (*SampleIterator, error) {
+	if windowUs <= 0 {
+		return nil, errors.New("Invalid window size")
+	}
+
+	evCap, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	boxes, err := r.Boxes()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SampleIterator{evCap: evCap, boxes: boxes, windowUs: windowUs}, nil
+}
+
+// Next advances to the next sample, returning false when there are no samples left
+func (it *SampleIterator) Next() bool {
+	if it.next >= len(it.boxes) {
+		return false
+	}
+
+	ts := it.boxes[it.next].Ts
+	last := it.next
+	for last < len(it.boxes) && it.boxes[last].Ts == ts {
+		last++
+	}
+
+	// boxes annotate the scene at ts, so events at ts are included
+	start, end := ts-it.windowUs+1, ts+1
+	ev := it.evCap.Events
+	first := sort.Search(len(ev), func(i int) bool { return ev[i].Ts >= start })
+	stop := sort.Search(len(ev), func(i int) bool { return ev[i].Ts >= end })
+
+	it.sample = Sample{
+		Start:   start,
+		End:     end,
+		Capture: event.EventCapture{Events: ev[first:stop], Width: it.evCap.Width, Height: it.evCap.Height},
+		Boxes:   it.boxes[it.next:last],
+	}
+	it.next = last
+	return true
+}
+
+// Sample returns the current sample
+func (it *SampleIterator) Sample() Sample {
+	return it.sample
+}
+
+// Index enumerates the recordings of Gen1 Automotive or 1Mpx. root must contain the train, val and test folders,
+// which are used as splits. Samples are the _td.dat files and their label is the split name, as recordings are
+// not classified.
+func Index(root string) (datasets.Index, error) {
+	idx, err := datasets.DiscoverSplits(root, map[string]string{"train": "train", "val": "val", "test": "test"}, ".dat")
+	if err != nil {
+		return idx, err
+	}
+
+	samples := []datasets.Sample{}
+	for _, s := range idx.Samples {
+		if strings.HasSuffix(s.Path, eventsSuffix) {
+			samples = append(samples, s)
+		}
+	}
+	return datasets.NewIndex(root, samples), nil
+}
@@ -0,0 +1,145 @@
+package automotive
+
+import (
+	"encoding/binary"
+	"image"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/format/numpy"
+)
+
+func writeDat(t *testing.T, path string, events []event.Event) {
+	data := []byte("% Data file containing CD events.\n% Version 2\n")
+	data = append(data, 0x0C, 8)
+	for _, ev := range events {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint32(b, uint32(ev.Ts))
+		binary.LittleEndian.PutUint32(b[4:], uint32(ev.Coords.X)|uint32(ev.Coords.Y)<<14|uint32(ev.P)<<28)
+		data = append(data, b...)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadBoxes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "17-04-13_15-05-43_1281500000_1341500000_bbox.npy")
+
+	boxes := []BBox{
+		{Ts: 100000, X: 10.5, Y: 20, W: 30, H: 40.25, ClassID: 0, Confidence: 1, TrackID: 3},
+		{Ts: 50000, X: 1, Y: 2, W: 3, H: 4, ClassID: 1, Confidence: 0.5, TrackID: 1},
+	}
+	if err := WriteBoxes(path, boxes); err != nil {
+		t.Fatalf("WriteBoxes() error = %v", err)
+	}
+
+	got, err := ReadBoxes(path)
+	want := []BBox{boxes[1], boxes[0]}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadBoxes() = %v, error = %v, want %v", got, err, want)
+	}
+
+	// older files name the confidence field class_confidence
+	a, err := numpy.NewArray([]numpy.Field{
+		{Name: "ts", Descr: "<u8"},
+		{Name: "x", Descr: "<f4"},
+		{Name: "y", Descr: "<f4"},
+		{Name: "w", Descr: "<f4"},
+		{Name: "h", Descr: "<f4"},
+		{Name: "class_id", Descr: "|u1"},
+		{Name: "class_confidence", Descr: "<f4"},
+		{Name: "track_id", Descr: "<u4"},
+	}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.SetInt("ts", 0, 7)
+	a.SetFloat("class_confidence", 0, 0.75)
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Write(f); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	got, err = ReadBoxes(path)
+	want = []BBox{{Ts: 7, Confidence: 0.75}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadBoxes() = %v, error = %v, want %v", got, err, want)
+	}
+}
+
+func TestRecording_Samples(t *testing.T) {
+	dir := t.TempDir()
+	r := Recording{FilePath: filepath.Join(dir, "moorea_2019-02-19_005_td_61500000_121500000_td.dat"), Width: Gen1Width, Height: Gen1Height}
+	r.BoxesPath = filepath.Join(dir, "moorea_2019-02-19_005_td_61500000_121500000_bbox.npy")
+
+	events := []event.Event{
+		{Coords: event.Point2D{X: 1, Y: 2}, Ts: 10, P: 1},
+		{Coords: event.Point2D{X: 303, Y: 239}, Ts: 60, P: 0},
+		{Coords: event.Point2D{X: 5, Y: 6}, Ts: 100, P: 1},
+		{Coords: event.Point2D{X: 7, Y: 8}, Ts: 101, P: 0},
+		{Coords: event.Point2D{X: 9, Y: 10}, Ts: 150, P: 1},
+	}
+	writeDat(t, r.FilePath, events)
+
+	boxes := []BBox{
+		{Ts: 100, X: 1, Y: 1, W: 10, H: 10, ClassID: 0, Confidence: 1, TrackID: 0},
+		{Ts: 100, X: 20, Y: 20, W: 5, H: 8, ClassID: 1, Confidence: 1, TrackID: 1},
+		{Ts: 150, X: 2, Y: 1, W: 10, H: 10, ClassID: 0, Confidence: 1, TrackID: 0},
+	}
+	if err := WriteBoxes(r.BoxesPath, boxes); err != nil {
+		t.Fatal(err)
+	}
+
+	it, err := r.Samples(50)
+	if err != nil {
+		t.Fatalf("Recording.Samples() error = %v", err)
+	}
+
+	got := []Sample{}
+	for it.Next() {
+		got = append(got, it.Sample())
+	}
+
+	want := []Sample{
+		{Start: 51, End: 101, Capture: event.EventCapture{Events: events[1:3], Width: Gen1Width, Height: Gen1Height}, Boxes: boxes[:2]},
+		{Start: 101, End: 151, Capture: event.EventCapture{Events: events[3:5], Width: Gen1Width, Height: Gen1Height}, Boxes: boxes[2:]},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Recording.Samples() = %v, want %v", got, want)
+	}
+
+	if w := Window(boxes, 101, 200); !reflect.DeepEqual(w, boxes[2:]) {
+		t.Errorf("Window() = %v, want %v", w, boxes[2:])
+	}
+	if rect := boxes[1].Rect(); rect != image.Rect(20, 20, 25, 28) {
+		t.Errorf("BBox.Rect() = %v", rect)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"train/a_td.dat", "train/a_bbox.npy", "val/b_td.dat", "test/c_td.dat", "test/c_bbox.npy"} {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	idx, err := Index(root)
+	if err != nil || len(idx.Samples) != 3 || len(idx.Split("train").Samples) != 1 {
+		t.Errorf("Index() = %v, error = %v", idx, err)
+	}
+}
@@ -0,0 +1,208 @@
+package datasets
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Sample represents a dataset entry found on disk
+type Sample struct {
+	Path    string `json:"path"`            // path of the sample file
+	RelPath string `json:"relPath"`         // path relative to the dataset root
+	Label   string `json:"label"`           // class label, taken from the name of the folder containing the sample
+	Split   string `json:"split,omitempty"` // predefined split of the dataset, such as "train" or "test", if any
+}
+
+// Discover walks root and returns every file with one of the given extensions, such as ".bin" or ".dat", sorted by path.
+// If no extension is given, every file is returned. Labels are derived from the folder structure, so
+// Caltech101/accordion/image_0005.bin is labeled "accordion".
+func Discover(root string, extensions ...string) ([]Sample, error) {
+	samples := []Sample{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !hasExtension(path, extensions) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		label := ""
+		if dir := filepath.Dir(rel); dir != "." {
+			label = filepath.Base(dir)
+		}
+
+		samples = append(samples, Sample{Path: path, RelPath: rel, Label: label})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i].RelPath < samples[j].RelPath })
+	return samples, nil
+}
+
+func hasExtension(path string, extensions []string) bool {
+	if len(extensions) == 0 {
+		return true
+	}
+	ext := filepath.Ext(path)
+	for _, e := range extensions {
+		if strings.EqualFold(ext, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// Job is a sample handed to a Batch processing function
+type Job struct {
+	Sample
+	OutputPath string // path mirroring the sample under the batch output root, empty if no output root is set
+}
+
+// CreateOutput creates the output file of the job along with its parent folders
+func (j Job) CreateOutput() (*os.File, error) {
+	if j.OutputPath == "" {
+		return nil, fmt.Errorf("No output path for %s", j.RelPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(j.OutputPath), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(j.OutputPath)
+}
+
+// Progress reports the completion of a sample
+type Progress struct {
+	Done   int    // number of processed samples, including this one
+	Total  int    // number of samples in the batch
+	Sample Sample // processed sample
+	Err    error  // error returned while processing the sample
+}
+
+// SampleError is the error returned while processing a sample
+type SampleError struct {
+	Sample Sample
+	Err    error
+}
+
+func (e SampleError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Sample.RelPath, e.Err)
+}
+
+func (e SampleError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError aggregates the errors of every failed sample of a batch, in sample order
+type BatchError []SampleError
+
+func (e BatchError) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%d samples failed, first error: %v", len(e), e[0])
+}
+
+// Batch processes dataset samples concurrently
+type Batch struct {
+	Workers         int            // number of concurrent workers, defaults to the number of CPUs
+	OutputRoot      string         // root of the mirrored output tree, optional
+	OutputExtension string         // extension of output files, such as ".png". Keeps the sample extension if empty
+	Progress        func(Progress) // called after each sample, never concurrently
+}
+
+func (b Batch) job(s Sample) Job {
+	j := Job{Sample: s}
+	if b.OutputRoot != "" {
+		rel := s.RelPath
+		if b.OutputExtension != "" {
+			rel = strings.TrimSuffix(rel, filepath.Ext(rel)) + b.OutputExtension
+		}
+		j.OutputPath = filepath.Join(b.OutputRoot, rel)
+	}
+	return j
+}
+
+// Run calls fn for every sample with a bounded number of workers. A failing sample does not stop the batch; all
+// failures are returned as a BatchError. When ctx is cancelled, pending samples are skipped and ctx.Err() is returned.
+func (b Batch) Run(ctx context.Context, samples []Sample, fn func(ctx context.Context, job Job) error) error {
+	workers := b.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	type result struct {
+		idx int
+		err error
+	}
+
+	jobs := make(chan int)
+	results := make(chan result)
+
+	wg := sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				results <- result{idx: idx, err: fn(ctx, b.job(samples[idx]))}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for idx := range samples {
+			select {
+			case jobs <- idx:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	errs := make([]error, len(samples))
+	done := 0
+	for r := range results {
+		done++
+		errs[r.idx] = r.err
+		if b.Progress != nil {
+			b.Progress(Progress{Done: done, Total: len(samples), Sample: samples[r.idx], Err: r.err})
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	failed := BatchError{}
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, SampleError{Sample: samples[i], Err: err})
+		}
+	}
+
+	if len(failed) > 0 {
+		return failed
+	}
+	return nil
+}
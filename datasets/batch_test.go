@@ -0,0 +1,114 @@
+package datasets
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func createFiles(t *testing.T, root string, files ...string) {
+	for _, f := range files {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	createFiles(t, root, "ant/image_0002.bin", "ant/image_0001.bin", "beaver/image_0001.BIN", "beaver/notes.txt", "readme.bin")
+
+	got, err := Discover(root, ".bin")
+	if err != nil {
+		t.Fatalf("Discover() error = %v", err)
+	}
+
+	want := []Sample{
+		{Path: filepath.Join(root, "ant/image_0001.bin"), RelPath: filepath.Join("ant", "image_0001.bin"), Label: "ant"},
+		{Path: filepath.Join(root, "ant/image_0002.bin"), RelPath: filepath.Join("ant", "image_0002.bin"), Label: "ant"},
+		{Path: filepath.Join(root, "beaver/image_0001.BIN"), RelPath: filepath.Join("beaver", "image_0001.BIN"), Label: "beaver"},
+		{Path: filepath.Join(root, "readme.bin"), RelPath: "readme.bin", Label: ""},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Discover() = %v, want %v", got, want)
+	}
+
+	if _, err := Discover(filepath.Join(root, "missing")); err == nil {
+		t.Errorf("Discover() on a missing folder should return an error")
+	}
+}
+
+func TestBatchRun(t *testing.T) {
+	root := t.TempDir()
+	out := t.TempDir()
+	createFiles(t, root, "a/1.bin", "a/2.bin", "b/1.bin", "b/2.bin")
+
+	samples, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	progress := []int{}
+	b := Batch{
+		Workers:         2,
+		OutputRoot:      out,
+		OutputExtension: ".txt",
+		Progress:        func(p Progress) { progress = append(progress, p.Done) },
+	}
+
+	err = b.Run(context.Background(), samples, func(ctx context.Context, job Job) error {
+		if job.Label == "b" && filepath.Base(job.Path) == "2.bin" {
+			return errors.New("failed")
+		}
+		f, err := job.CreateOutput()
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = f.WriteString(job.Label)
+		return err
+	})
+
+	batchErr, ok := err.(BatchError)
+	if !ok || len(batchErr) != 1 || batchErr[0].Sample != samples[3] {
+		t.Fatalf("Run() error = %v, want a BatchError for the last sample", err)
+	}
+
+	if !reflect.DeepEqual(progress, []int{1, 2, 3, 4}) {
+		t.Errorf("Run() progress = %v, want one report per sample", progress)
+	}
+
+	data, err := os.ReadFile(filepath.Join(out, "a", "2.txt"))
+	if err != nil || string(data) != "a" {
+		t.Errorf("Run() should write outputs to a mirrored tree, got %q, error = %v", data, err)
+	}
+}
+
+func TestBatchRunCancel(t *testing.T) {
+	samples := make([]Sample, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	processed := int32(0)
+	err := Batch{Workers: 1}.Run(ctx, samples, func(ctx context.Context, job Job) error {
+		if atomic.AddInt32(&processed, 1) == 5 {
+			cancel()
+		}
+		return nil
+	})
+
+	if err != context.Canceled {
+		t.Errorf("Run() error = %v, want %v", err, context.Canceled)
+	}
+	if processed >= 100 {
+		t.Errorf("Run() should stop processing samples after cancellation")
+	}
+}
@@ -0,0 +1,84 @@
+package cifar10dvs
+
+import (
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/datasets"
+	"github.com/ffardo/go-event-vision/format/aedat"
+)
+
+const (
+	// Width of the DVS128 sensor
+	Width = 128
+	// Height of the DVS128 sensor
+	Height = 128
+)
+
+// ClassNames lists the class folders of the dataset
+var ClassNames = []string{
+	"airplane",
+	"automobile",
+	"bird",
+	"cat",
+	"deer",
+	"dog",
+	"frog",
+	"horse",
+	"ship",
+	"truck",
+}
+
+// Cifar10Dvs implements DatasetReader interface for CIFAR10-DVS samples.
+// Samples are stored as DVS128 address and timestamp pairs. The sensor was mounted rotated, so coordinates and
+// polarity are remapped on read to show images upright, as done by the reference loaders.
+type Cifar10Dvs struct {
+	FilePath string
+}
+
+func (c Cifar10Dvs) file() aedat.Aedat2 {
+	return aedat.Aedat2{FilePath: c.FilePath, Address: aedat.DVS128Address}
+}
+
+// orient maps between the recorded and the upright orientation. It is its own inverse.
+func orient(ev event.Event) event.Event {
+	return event.Event{
+		Coords: event.Point2D{X: Width - 1 - ev.Coords.Y, Y: Height - 1 - ev.Coords.X},
+		Ts:     ev.Ts,
+		P:      1 - ev.P,
+	}
+}
+
+// Read event capture for an entry in the dataset
+func (c Cifar10Dvs) Read() (event.EventCapture, error) {
+	evCap, err := c.file().ReadEvents()
+	if err != nil {
+		return event.EventCapture{}, err
+	}
+
+	for i, ev := range evCap.Events {
+		evCap.Events[i] = orient(ev)
+	}
+
+	evCap.Width = Width
+	evCap.Height = Height
+	return evCap, nil
+}
+
+// Write capture to a dataset. Should be used only for data augmentation.
+func (c Cifar10Dvs) Write(evCap event.EventCapture) error {
+	ev := make([]event.Event, len(evCap.Events))
+	for i, e := range evCap.Events {
+		ev[i] = orient(e)
+	}
+
+	return c.file().WriteEvents(event.EventCapture{Events: ev, Width: evCap.Width, Height: evCap.Height})
+}
+
+// Index enumerates the samples of CIFAR10-DVS. root must contain one folder per class, named as in ClassNames.
+// The dataset has no official split, so Index.StratifiedSplit or Index.KFold can be used to build one.
+func Index(root string) (datasets.Index, error) {
+	samples, err := datasets.Discover(root, ".aedat")
+	if err != nil {
+		return datasets.Index{}, err
+	}
+	return datasets.NewIndex(root, samples), nil
+}
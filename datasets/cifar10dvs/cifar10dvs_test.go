@@ -0,0 +1,65 @@
+package cifar10dvs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/format/aedat"
+)
+
+func TestCifar10Dvs_Read(t *testing.T) {
+	dir := t.TempDir()
+	c := Cifar10Dvs{FilePath: filepath.Join(dir, "cifar10_airplane_0.aedat")}
+
+	evCap := event.EventCapture{
+		Events: []event.Event{
+			{Coords: event.Point2D{X: 10, Y: 30}, Ts: 937, P: 1},
+			{Coords: event.Point2D{X: 127, Y: 0}, Ts: 1030, P: 0},
+			{Coords: event.Point2D{X: 0, Y: 127}, Ts: 1 << 31, P: 1},
+		},
+		Width:  Width,
+		Height: Height,
+	}
+
+	if err := c.Write(evCap); err != nil {
+		t.Fatalf("Cifar10Dvs.Write() error = %v", err)
+	}
+
+	got, err := c.Read()
+	if err != nil {
+		t.Fatalf("Cifar10Dvs.Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, evCap) {
+		t.Errorf("Cifar10Dvs.Read() = %v, want %v", got, evCap)
+	}
+
+	raw, err := aedat.Aedat2{FilePath: c.FilePath, Address: aedat.DVS128Address}.ReadEvents()
+	if err != nil {
+		t.Fatalf("Aedat2.ReadEvents() error = %v", err)
+	}
+	want := event.Event{Coords: event.Point2D{X: 97, Y: 117}, Ts: 937, P: 0}
+	if len(raw.Events) != 3 || raw.Events[0] != want {
+		t.Errorf("Aedat2.ReadEvents() = %v, want first event %v", raw.Events, want)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"airplane/cifar10_airplane_0.aedat", "truck/cifar10_truck_0.aedat", "truck/notes.txt"} {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	idx, err := Index(root)
+	if err != nil || len(idx.Samples) != 2 || !reflect.DeepEqual(idx.Classes, []string{"airplane", "truck"}) {
+		t.Errorf("Index() = %v, error = %v", idx, err)
+	}
+}
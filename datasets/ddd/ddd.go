@@ -0,0 +1,338 @@
+/*
+ddd implements support for the DDD17 and DDD20 driving datasets.
+
+The original recordings are HDF5 files, which are read through an export layout so no native
+dependency is needed. Each exported recording is a folder with the following files:
+
+	events.npy   DVS events, either a structured array with t, x, y and p fields or an
+	             N x 4 integer array with t, x, y, p columns. t is expressed in microsseconds
+	vehicle.csv  vehicle signals. The first column is the timestamp in microsseconds and the
+	             header holds the signal names, such as steering_wheel_angle, vehicle_speed,
+	             accelerator_pedal_position or brake_pedal_status. Signals are sampled
+	             asynchronously, so cells of signals without a sample at a timestamp are empty
+	frames.csv   APS frames, optional. Each row holds a timestamp and the path of an image file,
+	             such as a PNG, relative to the recording folder
+
+The layout can be produced with h5py by iterating over the DVS event packets and the vehicle
+signal datasets of a recording and writing them with numpy.save and the csv module.
+*/
+package ddd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // frames may be exported as JPEG
+	_ "image/png"  // frames may be exported as PNG
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/format/numpy"
+)
+
+const (
+	// Width of the DAVIS346 sensor
+	Width = 346
+	// Height of the DAVIS346 sensor
+	Height = 260
+
+	// SteeringWheelAngle is the name of the steering wheel angle signal
+	SteeringWheelAngle = "steering_wheel_angle"
+	// VehicleSpeed is the name of the vehicle speed signal
+	VehicleSpeed = "vehicle_speed"
+	// AcceleratorPedalPosition is the name of the accelerator pedal position signal
+	AcceleratorPedalPosition = "accelerator_pedal_position"
+	// BrakePedalStatus is the name of the brake pedal status signal
+	BrakePedalStatus = "brake_pedal_status"
+)
+
+// DiscreteSignals holds the names of boolean and enumerated signals, whose values are held until the next sample
+// instead of being interpolated
+var DiscreteSignals = map[string]bool{
+	BrakePedalStatus:             true,
+	"parking_brake_status":       true,
+	"transmission_gear_position": true,
+	"turn_signal_status":         true,
+	"headlamp_status":            true,
+	"high_beam_status":           true,
+	"windshield_wiper_status":    true,
+	"door_status":                true,
+	"ignition_status":            true,
+}
+
+// Recording implements DatasetReader interface for an exported DDD17 or DDD20 recording
+type Recording struct {
+	Dir string
+}
+
+// Signal holds the samples of a vehicle signal, sorted by timestamp
+type Signal struct {
+	Ts       []int
+	Values   []float64
+	Discrete bool // values are held until the next sample instead of being interpolated
+}
+
+// Frame references an APS frame of a recording
+type Frame struct {
+	Ts   int
+	Path string
+}
+
+// Sample is a time window of a recording with the vehicle signals at the end of the window
+type Sample struct {
+	Start   int                // window start timestamp, inclusive
+	End     int                // window end timestamp, exclusive
+	Capture event.EventCapture // events of the window
+	Signals map[string]float64 // vehicle signals at the end of the window, as returned by Signal.At
+	Frame   *Frame             // most recent APS frame at the end of the window, if any
+}
+
+// Read event capture for the whole recording
+func (r Recording) Read() (event.EventCapture, error) {
+	a, err := numpy.ReadFile(filepath.Join(r.Dir, "events.npy"))
+	if err != nil {
+		return event.EventCapture{}, err
+	}
+
+	columns := make([][]int64, 4)
+
+	if len(a.Fields) == 1 && a.Fields[0].Name == "" {
+		if len(a.Shape) != 2 || a.Shape[1] != 4 || a.FortranOrder {
+			return event.EventCapture{}, errors.New("Events must be a structured or a row-major N x 4 array")
+		}
+		values, err := a.Ints("")
+		if err != nil {
+			return event.EventCapture{}, err
+		}
+		for c := range columns {
+			columns[c] = make([]int64, a.Shape[0])
+			for i := range columns[c] {
+				columns[c][i] = values[i*4+c]
+			}
+		}
+	} else {
+		for c, name := range []string{"t", "x", "y", "p"} {
+			columns[c], err = a.Ints(name)
+			if err != nil {
+				return event.EventCapture{}, err
+			}
+		}
+	}
+
+	ev := make([]event.Event, len(columns[0]))
+	for i := range ev {
+		ev[i] = event.Event{
+			Coords: event.Point2D{X: int(columns[1][i]), Y: int(columns[2][i])},
+			Ts:     int(columns[0][i]),
+			P:      int(columns[3][i]),
+		}
+	}
+
+	return event.EventCapture{Events: ev, Width: Width, Height: Height}, nil
+}
+
+func readCSV(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	cr := csv.NewReader(f)
+	cr.TrimLeadingSpace = true
+	return cr.ReadAll()
+}
+
+// VehicleSignals reads every vehicle signal of the recording, keyed by signal name.
+// Signals listed in DiscreteSignals are marked as Discrete.
+func (r Recording) VehicleSignals() (map[string]Signal, error) {
+	records, err := readCSV(filepath.Join(r.Dir, "vehicle.csv"))
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("Empty vehicle signals file")
+	}
+
+	header := records[0]
+	signals := make(map[string]Signal, len(header)-1)
+
+	for line, rec := range records[1:] {
+		ts, err := strconv.Atoi(rec[0])
+		if err != nil {
+			return nil, fmt.Errorf("vehicle.csv line %d: %v", line+2, err)
+		}
+
+		for c := 1; c < len(rec) && c < len(header); c++ {
+			if strings.TrimSpace(rec[c]) == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(rec[c], 64)
+			if err != nil {
+				return nil, fmt.Errorf("vehicle.csv line %d: %v", line+2, err)
+			}
+			s := signals[header[c]]
+			s.Discrete = DiscreteSignals[header[c]]
+			s.Ts = append(s.Ts, ts)
+			s.Values = append(s.Values, v)
+			signals[header[c]] = s
+		}
+	}
+
+	for name, s := range signals {
+		if !sort.IntsAreSorted(s.Ts) {
+			return nil, fmt.Errorf("Signal %s is not sorted by timestamp", name)
+		}
+	}
+
+	return signals, nil
+}
+
+// At returns the value of the signal at ts, linearly interpolated between the surrounding samples, or the value
+// of the previous sample for discrete signals. It returns false if ts is outside of the sampled range.
+func (s Signal) At(ts int) (float64, bool) {
+	n := len(s.Ts)
+	if n == 0 || ts < s.Ts[0] || ts > s.Ts[n-1] {
+		return 0, false
+	}
+
+	i := sort.SearchInts(s.Ts, ts)
+	if s.Ts[i] == ts {
+		return s.Values[i], true
+	}
+
+	if s.Discrete {
+		return s.Values[i-1], true
+	}
+	t0, t1 := s.Ts[i-1], s.Ts[i]
+	v0, v1 := s.Values[i-1], s.Values[i]
+	return v0 + (v1-v0)*float64(ts-t0)/float64(t1-t0), true
+}
+
+// Frames reads the APS frame references of the recording. Recordings without frames.csv have no frames.
+func (r Recording) Frames() ([]Frame, error) {
+	records, err := readCSV(filepath.Join(r.Dir, "frames.csv"))
+	if os.IsNotExist(err) {
+		return []Frame{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	frames := []Frame{}
+	for i, rec := range records {
+		ts, err := strconv.Atoi(rec[0])
+		if err != nil {
+			if i == 0 {
+				// header row
+				continue
+			}
+			return nil, fmt.Errorf("frames.csv line %d: %v", i+1, err)
+		}
+		if len(rec) < 2 {
+			return nil, fmt.Errorf("frames.csv line %d: missing path", i+1)
+		}
+		frames = append(frames, Frame{Ts: ts, Path: filepath.Join(r.Dir, rec[1])})
+	}
+
+	sort.SliceStable(frames, func(i, j int) bool { return frames[i].Ts < frames[j].Ts })
+	return frames, nil
+}
+
+// Image decodes the frame image
+func (f Frame) Image() (image.Image, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	return img, err
+}
+
+// Samples splits the recording into consecutive windows of windowUs microsseconds, starting at the first event.
+// Each sample holds the requested signals interpolated at the end of the window, for instance the steering wheel
+// angle to build steering prediction samples. Windows where a requested signal is not available are skipped, and
+// an error is returned if a requested signal is not recorded.
+func (r Recording) Samples(windowUs int, signalNames ...string) ([]Sample, error) {
+	if windowUs <= 0 {
+		return nil, errors.New("Invalid window size")
+	}
+
+	evCap, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	signals, err := r.VehicleSignals()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range signalNames {
+		if _, ok := signals[name]; !ok {
+			return nil, fmt.Errorf("Unknown vehicle signal %s", name)
+		}
+	}
+
+	frames, err := r.Frames()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := []Sample{}
+	ev := evCap.Events
+	if len(ev) == 0 {
+		return samples, nil
+	}
+
+	first, f := 0, 0
+	for start := ev[0].Ts; first < len(ev); start += windowUs {
+		end := start + windowUs
+
+		last := first
+		for last < len(ev) && ev[last].Ts < end {
+			last++
+		}
+
+		for f < len(frames) && frames[f].Ts < end {
+			f++
+		}
+
+		s := Sample{
+			Start:   start,
+			End:     end,
+			Capture: event.EventCapture{Events: ev[first:last], Width: evCap.Width, Height: evCap.Height},
+			Signals: make(map[string]float64, len(signalNames)),
+		}
+		if f > 0 {
+			frame := frames[f-1]
+			s.Frame = &frame
+		}
+
+		complete := true
+		for _, name := range signalNames {
+			v, ok := signals[name].At(end)
+			if !ok {
+				complete = false
+				break
+			}
+			s.Signals[name] = v
+		}
+
+		if complete {
+			samples = append(samples, s)
+		}
+		first = last
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,113 @@
+package ddd
+
+import (
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/format/numpy"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeRecording(t *testing.T, dir string, events []event.Event) {
+	a, err := numpy.NewArray([]numpy.Field{{Descr: "<i8"}}, len(events), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, ev := range events {
+		a.SetInt("", i*4, int64(ev.Ts))
+		a.SetInt("", i*4+1, int64(ev.Coords.X))
+		a.SetInt("", i*4+2, int64(ev.Coords.Y))
+		a.SetInt("", i*4+3, int64(ev.P))
+	}
+
+	f, err := os.Create(filepath.Join(dir, "events.npy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := a.Write(f); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, filepath.Join(dir, "vehicle.csv"), "timestamp,steering_wheel_angle,vehicle_speed,brake_pedal_status\n0,0.0,10,0\n1000,10.0,,1\n2000,20.0,30,0\n")
+
+	img, err := os.Create(filepath.Join(dir, "frame_0.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer img.Close()
+	png.Encode(img, image.NewGray(image.Rect(0, 0, 4, 3)))
+
+	writeFile(t, filepath.Join(dir, "frames.csv"), "timestamp,path\n400,frame_0.png\n")
+}
+
+func TestRecording(t *testing.T) {
+	dir := t.TempDir()
+	events := []event.Event{
+		{Coords: event.Point2D{X: 10, Y: 30}, Ts: 100, P: 1},
+		{Coords: event.Point2D{X: 345, Y: 259}, Ts: 600, P: 0},
+		{Coords: event.Point2D{X: 12, Y: 27}, Ts: 1200, P: 1},
+		{Coords: event.Point2D{X: 13, Y: 27}, Ts: 2500, P: 1},
+	}
+	writeRecording(t, dir, events)
+
+	r := Recording{Dir: dir}
+
+	evCap, err := r.Read()
+	if err != nil || !reflect.DeepEqual(evCap, event.EventCapture{Events: events, Width: Width, Height: Height}) {
+		t.Fatalf("Read() = %v, error = %v", evCap, err)
+	}
+
+	signals, err := r.VehicleSignals()
+	if err != nil {
+		t.Fatalf("VehicleSignals() error = %v", err)
+	}
+	if !reflect.DeepEqual(signals[VehicleSpeed], Signal{Ts: []int{0, 2000}, Values: []float64{10, 30}}) {
+		t.Errorf("VehicleSignals() speed = %v", signals[VehicleSpeed])
+	}
+	if v, ok := signals[SteeringWheelAngle].At(1500); !ok || math.Abs(v-15) > 1e-9 {
+		t.Errorf("Signal.At() = %v, %v, want 15", v, ok)
+	}
+	if _, ok := signals[SteeringWheelAngle].At(2001); ok {
+		t.Errorf("Signal.At() outside of sampled range should not be available")
+	}
+	if v, ok := signals[BrakePedalStatus].At(1999); !signals[BrakePedalStatus].Discrete || !ok || v != 1 {
+		t.Errorf("Signal.At() = %v, %v, want the held value 1", v, ok)
+	}
+
+	if _, err := r.Samples(900, "brake_pedal"); err == nil {
+		t.Errorf("Samples() with an unknown signal should return an error")
+	}
+
+	samples, err := r.Samples(900, SteeringWheelAngle, VehicleSpeed, BrakePedalStatus)
+	if err != nil {
+		t.Fatalf("Samples() error = %v", err)
+	}
+
+	// The third window ends after the last vehicle sample and is skipped
+	if len(samples) != 2 {
+		t.Fatalf("Samples() = %v, want 2 samples", samples)
+	}
+	if samples[0].Start != 100 || len(samples[0].Capture.Events) != 2 || samples[0].Signals[SteeringWheelAngle] != 10 || samples[0].Signals[VehicleSpeed] != 20 {
+		t.Errorf("Samples() first sample = %v", samples[0])
+	}
+	if samples[1].Frame == nil || samples[1].Frame.Ts != 400 || len(samples[1].Capture.Events) != 1 || samples[1].Signals[SteeringWheelAngle] != 19 || samples[1].Signals[BrakePedalStatus] != 1 {
+		t.Errorf("Samples() second sample = %v", samples[1])
+	}
+
+	img, err := samples[1].Frame.Image()
+	if err != nil || img.Bounds().Dx() != 4 {
+		t.Errorf("Frame.Image() = %v, error = %v", img, err)
+	}
+}
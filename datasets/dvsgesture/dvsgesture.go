@@ -0,0 +1,209 @@
+package dvsgesture
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/datasets"
+	"github.com/ffardo/go-event-vision/filter"
+	"github.com/ffardo/go-event-vision/format/aedat"
+)
+
+const (
+	// Width of the DVS128 sensor
+	Width = 128
+	// Height of the DVS128 sensor
+	Height = 128
+)
+
+// ClassNames lists the gesture names, where ClassNames[i] is the name of class i+1 in the label files
+var ClassNames = []string{
+	"hand_clapping",
+	"right_hand_wave",
+	"left_hand_wave",
+	"right_arm_clockwise",
+	"right_arm_counter_clockwise",
+	"left_arm_clockwise",
+	"left_arm_counter_clockwise",
+	"arm_roll",
+	"air_drums",
+	"air_guitar",
+	"other_gestures",
+}
+
+// DvsGesture implements DatasetReader interface for IBM DVS128 Gesture dataset recordings
+type DvsGesture struct {
+	FilePath   string // path of the AEDAT 3.1 recording
+	LabelsPath string // path of the labels file. Defaults to the recording path with the _labels.csv suffix
+}
+
+// Segment is a labeled time interval of a recording
+type Segment struct {
+	Label int // class as found in the labels file, from 1 to 11
+	Start int // start timestamp in microsseconds
+	End   int // end timestamp in microsseconds
+}
+
+// Gesture is a labeled gesture extracted from a recording. Event timestamps are relative to the segment start.
+type Gesture struct {
+	Segment
+	Name    string
+	Capture event.EventCapture
+}
+
+// Read event capture for a whole recording
+func (d DvsGesture) Read() (event.EventCapture, error) {
+	evCap, err := aedat.Aedat3{FilePath: d.FilePath}.ReadEvents()
+	if err != nil {
+		return event.EventCapture{}, err
+	}
+
+	evCap.Width = Width
+	evCap.Height = Height
+	return evCap, nil
+}
+
+// Write capture to a dataset. Should be used only for data augmentation.
+func (d DvsGesture) Write(evCap event.EventCapture) error {
+	return aedat.Aedat3{FilePath: d.FilePath}.WriteEvents(evCap)
+}
+
+func (d DvsGesture) labelsPath() string {
+	if d.LabelsPath != "" {
+		return d.LabelsPath
+	}
+	return strings.TrimSuffix(d.FilePath, filepath.Ext(d.FilePath)) + "_labels.csv"
+}
+
+// Segments reads the labeled segments of the recording
+func (d DvsGesture) Segments() ([]Segment, error) {
+	f, err := os.Open(d.labelsPath())
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return ReadLabels(f)
+}
+
+// ReadLabels reads labeled segments in the class,startTime_usec,endTime_usec CSV format of the dataset
+func ReadLabels(r io.Reader) ([]Segment, error) {
+	cr := csv.NewReader(bufio.NewReader(r))
+	cr.FieldsPerRecord = 3
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	segments := []Segment{}
+	for i, rec := range records {
+		if _, err := strconv.Atoi(rec[0]); err != nil && i == 0 {
+			// skip header row
+			continue
+		}
+
+		values := make([]int, 3)
+		for j, v := range rec {
+			values[j], err = strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if values[0] < 1 || values[0] > len(ClassNames) {
+			return nil, errors.New("Invalid gesture class " + rec[0])
+		}
+		segments = append(segments, Segment{Label: values[0], Start: values[1], End: values[2]})
+	}
+
+	return segments, nil
+}
+
+// Gestures reads the recording and splits it into one capture per labeled segment
+func (d DvsGesture) Gestures() ([]Gesture, error) {
+	segments, err := d.Segments()
+	if err != nil {
+		return nil, err
+	}
+
+	evCap, err := d.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	gestures := make([]Gesture, len(segments))
+	for i, s := range segments {
+		ev := filter.ByTime(evCap.Events, s.Start, s.End)
+		for j := range ev {
+			ev[j].Ts -= s.Start
+		}
+
+		gestures[i] = Gesture{
+			Segment: s,
+			Name:    ClassNames[s.Label-1],
+			Capture: event.EventCapture{Events: ev, Width: Width, Height: Height},
+		}
+	}
+
+	return gestures, nil
+}
+
+func readTrials(path string) (map[string]bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	trials := map[string]bool{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if t := strings.TrimSpace(s.Text()); t != "" {
+			trials[t] = true
+		}
+	}
+	return trials, s.Err()
+}
+
+// Index enumerates the recordings of the dataset. root must contain the recordings along with the trials_to_train.txt
+// and trials_to_test.txt files defining the predefined splits. Recordings hold several gestures, so samples are not labeled.
+func Index(root string) (datasets.Index, error) {
+	train, err := readTrials(filepath.Join(root, "trials_to_train.txt"))
+	if err != nil {
+		return datasets.Index{}, err
+	}
+	test, err := readTrials(filepath.Join(root, "trials_to_test.txt"))
+	if err != nil {
+		return datasets.Index{}, err
+	}
+
+	samples, err := datasets.Discover(root, ".aedat")
+	if err != nil {
+		return datasets.Index{}, err
+	}
+
+	dst := []datasets.Sample{}
+	for _, s := range samples {
+		name := filepath.Base(s.RelPath)
+		s.Label = ""
+		if train[name] {
+			s.Split = "train"
+		} else if test[name] {
+			s.Split = "test"
+		}
+		dst = append(dst, s)
+	}
+
+	return datasets.NewIndex(root, dst), nil
+}
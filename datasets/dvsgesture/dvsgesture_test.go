@@ -0,0 +1,103 @@
+package dvsgesture
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+func TestReadLabels(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Segment
+		wantErr bool
+	}{
+		{
+			name: "Test labels with header",
+			data: "class,startTime_usec,endTime_usec\n1,100,200\n11,300,400\n",
+			want: []Segment{{Label: 1, Start: 100, End: 200}, {Label: 11, Start: 300, End: 400}},
+		},
+		{
+			name: "Test labels without header",
+			data: "2,100,200\n",
+			want: []Segment{{Label: 2, Start: 100, End: 200}},
+		},
+		{name: "Test invalid class", data: "12,100,200\n", wantErr: true},
+		{name: "Test invalid timestamp", data: "1,100,abc\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadLabels(strings.NewReader(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReadLabels() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadLabels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDvsGesture_Gestures(t *testing.T) {
+	dir := t.TempDir()
+	d := DvsGesture{FilePath: filepath.Join(dir, "user01_fluorescent.aedat")}
+
+	recording := event.EventCapture{
+		Events: []event.Event{
+			{Coords: event.Point2D{X: 10, Y: 30}, Ts: 50, P: 1},
+			{Coords: event.Point2D{X: 127, Y: 20}, Ts: 150, P: 0},
+			{Coords: event.Point2D{X: 12, Y: 127}, Ts: 180, P: 1},
+			{Coords: event.Point2D{X: 33, Y: 3}, Ts: 350, P: 1},
+			{Coords: event.Point2D{X: 14, Y: 23}, Ts: 1 << 32, P: 0},
+		},
+		Width:  Width,
+		Height: Height,
+	}
+
+	if err := d.Write(recording); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	got, err := d.Read()
+	if err != nil || !reflect.DeepEqual(got, recording) {
+		t.Fatalf("Read() = %v, error = %v, want %v", got, err, recording)
+	}
+
+	labels := "class,startTime_usec,endTime_usec\n1,100,200\n8,300,400\n"
+	if err := os.WriteFile(filepath.Join(dir, "user01_fluorescent_labels.csv"), []byte(labels), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gestures, err := d.Gestures()
+	if err != nil {
+		t.Fatalf("Gestures() error = %v", err)
+	}
+
+	want := []Gesture{
+		{
+			Segment: Segment{Label: 1, Start: 100, End: 200},
+			Name:    "hand_clapping",
+			Capture: event.EventCapture{Events: []event.Event{
+				{Coords: event.Point2D{X: 127, Y: 20}, Ts: 50, P: 0},
+				{Coords: event.Point2D{X: 12, Y: 127}, Ts: 80, P: 1},
+			}, Width: Width, Height: Height},
+		},
+		{
+			Segment: Segment{Label: 8, Start: 300, End: 400},
+			Name:    "arm_roll",
+			Capture: event.EventCapture{Events: []event.Event{
+				{Coords: event.Point2D{X: 33, Y: 3}, Ts: 50, P: 1},
+			}, Width: Width, Height: Height},
+		},
+	}
+
+	if !reflect.DeepEqual(gestures, want) {
+		t.Errorf("Gestures() = %v, want %v", gestures, want)
+	}
+}
@@ -0,0 +1,227 @@
+package datasets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Index enumerates the samples of a dataset along with their labels.
+// Class IDs are the positions of the labels in Classes, which is sorted alphabetically.
+type Index struct {
+	Root    string   `json:"root"`
+	Classes []string `json:"classes"`
+	Samples []Sample `json:"samples"`
+}
+
+// Fold is a train/test partition of an Index
+type Fold struct {
+	Train Index
+	Test  Index
+}
+
+// NewIndex creates an Index from samples found under root
+func NewIndex(root string, samples []Sample) Index {
+	labels := map[string]bool{}
+	for _, s := range samples {
+		labels[s.Label] = true
+	}
+
+	classes := make([]string, 0, len(labels))
+	for l := range labels {
+		classes = append(classes, l)
+	}
+	sort.Strings(classes)
+
+	return Index{Root: root, Classes: classes, Samples: samples}
+}
+
+// DiscoverSplits creates an Index for datasets whose top level folders define predefined splits, such as the
+// Train and Test folders of N-MNIST. splits maps folder names to split names and files outside those folders are ignored.
+func DiscoverSplits(root string, splits map[string]string, extensions ...string) (Index, error) {
+	samples, err := Discover(root, extensions...)
+	if err != nil {
+		return Index{}, err
+	}
+
+	dst := []Sample{}
+	for _, s := range samples {
+		top := strings.SplitN(filepath.ToSlash(s.RelPath), "/", 2)[0]
+		split, ok := splits[top]
+		if !ok || top == s.RelPath {
+			continue
+		}
+		s.Split = split
+		dst = append(dst, s)
+	}
+
+	return NewIndex(root, dst), nil
+}
+
+// ClassID returns the ID of a label, or -1 if the label is unknown
+func (i Index) ClassID(label string) int {
+	idx := sort.SearchStrings(i.Classes, label)
+	if idx < len(i.Classes) && i.Classes[idx] == label {
+		return idx
+	}
+	return -1
+}
+
+// subset creates an Index with the same classes and the samples at the given positions, in their original order
+func (i Index) subset(positions []int) Index {
+	sort.Ints(positions)
+
+	samples := make([]Sample, len(positions))
+	for j, p := range positions {
+		samples[j] = i.Samples[p]
+	}
+	return Index{Root: i.Root, Classes: i.Classes, Samples: samples}
+}
+
+// Split returns the samples belonging to a predefined split, such as "train" or "test"
+func (i Index) Split(split string) Index {
+	positions := []int{}
+	for p, s := range i.Samples {
+		if s.Split == split {
+			positions = append(positions, p)
+		}
+	}
+	return i.subset(positions)
+}
+
+// shuffledClasses groups sample positions by label and shuffles each group with the given seed.
+// Samples whose label is not in Classes are left out.
+func (i Index) shuffledClasses(seed int64) [][]int {
+	random := rand.New(rand.NewSource(seed))
+
+	groups := make([][]int, len(i.Classes))
+	for p, s := range i.Samples {
+		id := i.ClassID(s.Label)
+		if id < 0 {
+			continue
+		}
+		groups[id] = append(groups[id], p)
+	}
+
+	for _, g := range groups {
+		random.Shuffle(len(g), func(a, b int) { g[a], g[b] = g[b], g[a] })
+	}
+	return groups
+}
+
+// StratifiedSplit randomly partitions the samples so that each class contributes testFraction of its samples to
+// the test set. testFraction must be in [0, 1]. The same seed always produces the same partition.
+func (i Index) StratifiedSplit(testFraction float64, seed int64) (train, test Index, err error) {
+	if !(testFraction >= 0 && testFraction <= 1) {
+		return Index{}, Index{}, errors.New("Invalid test fraction")
+	}
+
+	trainPos, testPos := []int{}, []int{}
+
+	for _, g := range i.shuffledClasses(seed) {
+		n := int(math.Round(float64(len(g)) * testFraction))
+		testPos = append(testPos, g[:n]...)
+		trainPos = append(trainPos, g[n:]...)
+	}
+
+	return i.subset(trainPos), i.subset(testPos), nil
+}
+
+// KFold randomly partitions the samples into k stratified folds and returns, for each fold, the fold as test set
+// and the remaining samples as training set. The same seed always produces the same folds.
+func (i Index) KFold(k int, seed int64) ([]Fold, error) {
+	if k < 2 || k > len(i.Samples) {
+		return nil, errors.New("Invalid number of folds")
+	}
+
+	assigned := make([][]int, k)
+	next := 0
+	for _, g := range i.shuffledClasses(seed) {
+		for _, p := range g {
+			assigned[next] = append(assigned[next], p)
+			next = (next + 1) % k
+		}
+	}
+
+	folds := make([]Fold, k)
+	for f := range folds {
+		trainPos := []int{}
+		for o := range assigned {
+			if o != f {
+				trainPos = append(trainPos, assigned[o]...)
+			}
+		}
+		folds[f] = Fold{Train: i.subset(trainPos), Test: i.subset(append([]int{}, assigned[f]...))}
+	}
+	return folds, nil
+}
+
+// Relocate returns a copy of the index with sample paths rebuilt under a new root, for manifests created on
+// another machine
+func (i Index) Relocate(root string) Index {
+	samples := make([]Sample, len(i.Samples))
+	for j, s := range i.Samples {
+		s.Path = filepath.Join(root, s.RelPath)
+		samples[j] = s
+	}
+	return Index{Root: root, Classes: i.Classes, Samples: samples}
+}
+
+// WriteManifest writes the index to w in JSON format
+func (i Index) WriteManifest(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(i)
+}
+
+// ReadManifest reads an index written by WriteManifest.
+// An error is returned if the classes are not sorted or a sample label is not one of the classes.
+func ReadManifest(r io.Reader) (Index, error) {
+	i := Index{}
+	if err := json.NewDecoder(r).Decode(&i); err != nil {
+		return Index{}, err
+	}
+
+	if !sort.StringsAreSorted(i.Classes) {
+		return Index{}, errors.New("Manifest classes are not sorted")
+	}
+	for _, s := range i.Samples {
+		if i.ClassID(s.Label) < 0 {
+			return Index{}, fmt.Errorf("Unknown label %q in manifest", s.Label)
+		}
+	}
+	return i, nil
+}
+
+// SaveManifest writes the index to a manifest file
+func (i Index) SaveManifest(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := i.WriteManifest(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadManifest reads an index from a manifest file
+func LoadManifest(path string) (Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Index{}, err
+	}
+	defer f.Close()
+
+	return ReadManifest(f)
+}
@@ -0,0 +1,149 @@
+package datasets
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testIndex() Index {
+	samples := []Sample{}
+	for _, label := range []string{"cars", "background"} {
+		for i := 0; i < 10; i++ {
+			rel := filepath.Join(label, fmt.Sprintf("obj_%d.dat", i))
+			samples = append(samples, Sample{Path: filepath.Join("/data", rel), RelPath: rel, Label: label})
+		}
+	}
+	return NewIndex("/data", samples)
+}
+
+func countLabels(i Index) map[string]int {
+	c := map[string]int{}
+	for _, s := range i.Samples {
+		c[s.Label]++
+	}
+	return c
+}
+
+func TestDiscoverSplits(t *testing.T) {
+	root := t.TempDir()
+	createFiles(t, root, "n-cars_train/cars/a.dat", "n-cars_train/background/b.dat", "n-cars_test/cars/c.dat", "other/d.dat", "e.dat")
+
+	idx, err := DiscoverSplits(root, map[string]string{"n-cars_train": "train", "n-cars_test": "test"}, ".dat")
+	if err != nil {
+		t.Fatalf("DiscoverSplits() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(idx.Classes, []string{"background", "cars"}) {
+		t.Errorf("DiscoverSplits() classes = %v", idx.Classes)
+	}
+	if len(idx.Split("train").Samples) != 2 || len(idx.Split("test").Samples) != 1 || len(idx.Samples) != 3 {
+		t.Errorf("DiscoverSplits() samples = %v", idx.Samples)
+	}
+	if idx.ClassID("cars") != 1 || idx.ClassID("boats") != -1 {
+		t.Errorf("ClassID() should return positions in Classes")
+	}
+}
+
+func TestStratifiedSplit(t *testing.T) {
+	idx := testIndex()
+
+	train, test, err := idx.StratifiedSplit(0.2, 1)
+	if err != nil {
+		t.Fatalf("StratifiedSplit() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(countLabels(test), map[string]int{"cars": 2, "background": 2}) {
+		t.Errorf("StratifiedSplit() test labels = %v", countLabels(test))
+	}
+	if !reflect.DeepEqual(countLabels(train), map[string]int{"cars": 8, "background": 8}) {
+		t.Errorf("StratifiedSplit() train labels = %v", countLabels(train))
+	}
+
+	train2, test2, _ := idx.StratifiedSplit(0.2, 1)
+	if !reflect.DeepEqual(train, train2) || !reflect.DeepEqual(test, test2) {
+		t.Errorf("StratifiedSplit() should be reproducible with the same seed")
+	}
+
+	idx.Samples = append(idx.Samples, Sample{Label: "boats"})
+	if train, test, _ := idx.StratifiedSplit(0.2, 1); len(train.Samples)+len(test.Samples) != 20 {
+		t.Errorf("StratifiedSplit() should leave out samples with unknown labels")
+	}
+
+	for _, f := range []float64{-0.1, 1.5, math.NaN()} {
+		if _, _, err := idx.StratifiedSplit(f, 1); err == nil {
+			t.Errorf("StratifiedSplit() with a test fraction of %v should return an error", f)
+		}
+	}
+}
+
+func TestKFold(t *testing.T) {
+	idx := testIndex()
+
+	folds, err := idx.KFold(5, 1)
+	if err != nil {
+		t.Fatalf("KFold() error = %v", err)
+	}
+
+	seen := map[string]int{}
+	for _, f := range folds {
+		if len(f.Train.Samples) != 16 || !reflect.DeepEqual(countLabels(f.Test), map[string]int{"cars": 2, "background": 2}) {
+			t.Errorf("KFold() fold should be stratified, test labels = %v", countLabels(f.Test))
+		}
+		for _, s := range f.Test.Samples {
+			seen[s.Path]++
+		}
+	}
+
+	if len(seen) != 20 {
+		t.Errorf("KFold() every sample should be tested exactly once, got %v", seen)
+	}
+
+	if _, err := idx.KFold(1, 1); err == nil {
+		t.Errorf("KFold() with a single fold should return an error")
+	}
+}
+
+func TestManifest(t *testing.T) {
+	idx := testIndex()
+	buf := &bytes.Buffer{}
+
+	if err := idx.WriteManifest(buf); err != nil {
+		t.Fatalf("WriteManifest() error = %v", err)
+	}
+
+	got, err := ReadManifest(buf)
+	if err != nil {
+		t.Fatalf("ReadManifest() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, idx) {
+		t.Errorf("ReadManifest() = %v, want %v", got, idx)
+	}
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := idx.SaveManifest(path); err != nil {
+		t.Fatalf("SaveManifest() error = %v", err)
+	}
+	if got, err := LoadManifest(path); err != nil || !reflect.DeepEqual(got, idx) {
+		t.Errorf("LoadManifest() = %v, error = %v", got, err)
+	}
+
+	invalid := []string{
+		`{"classes": ["cars", "background"], "samples": []}`,
+		`{"classes": ["background"], "samples": [{"label": "cars"}]}`,
+	}
+	for _, m := range invalid {
+		if _, err := ReadManifest(strings.NewReader(m)); err == nil {
+			t.Errorf("ReadManifest(%s) should return an error", m)
+		}
+	}
+
+	moved := idx.Relocate("/mnt")
+	if moved.Samples[0].Path != filepath.Join("/mnt", idx.Samples[0].RelPath) {
+		t.Errorf("Relocate() path = %s", moved.Samples[0].Path)
+	}
+}
@@ -2,6 +2,7 @@ package ncars
 
 import (
 	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/datasets"
 	"github.com/ffardo/go-event-vision/format/prophesee"
 )
 
@@ -23,3 +24,9 @@ func (n Ncars) Write(evCap event.EventCapture) error {
 
 	return atis.WriteEvents(evCap)
 }
+
+// Index enumerates the samples of N-CARS. root must contain the n-cars_train and n-cars_test folders, each with
+// the cars and background folders.
+func Index(root string) (datasets.Index, error) {
+	return datasets.DiscoverSplits(root, map[string]string{"n-cars_train": "train", "n-cars_test": "test"}, ".dat")
+}
@@ -0,0 +1,125 @@
+package neuromorphic
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+// Annotation holds the object annotation of an N-Caltech101 sample, from the Caltech101_annotations folder.
+// Coordinates match the stabilized sample, so Displace is needed to compare them with raw events.
+type Annotation struct {
+	Box     []event.Point2D // corners of the bounding box, as a closed polygon
+	Contour []event.Point2D // object contour
+}
+
+// AnnotationPath returns the path of the annotation of an N-Caltech101 sample, such as
+// Caltech101_annotations/airplanes/annotation_0001.bin for Caltech101/airplanes/image_0001.bin
+func AnnotationPath(annotationsRoot, samplePath string) string {
+	category := filepath.Base(filepath.Dir(samplePath))
+	name := strings.Replace(filepath.Base(samplePath), "image_", "annotation_", 1)
+	return filepath.Join(annotationsRoot, category, name)
+}
+
+// readPoints reads a 2 x N int16 matrix, stored column-major after its dimensions, as N points
+func readPoints(r io.Reader) ([]event.Point2D, error) {
+	dims := make([]int16, 2)
+	if err := binary.Read(r, binary.LittleEndian, dims); err != nil {
+		return nil, errors.New("Truncated annotation")
+	}
+	if dims[0] != 2 || dims[1] < 0 {
+		return nil, errors.New("Invalid annotation dimensions")
+	}
+
+	values := make([]int16, 2*int(dims[1]))
+	if err := binary.Read(r, binary.LittleEndian, values); err != nil {
+		return nil, errors.New("Truncated annotation")
+	}
+
+	points := make([]event.Point2D, dims[1])
+	for i := range points {
+		points[i] = event.Point2D{X: int(values[2*i]), Y: int(values[2*i+1])}
+	}
+	return points, nil
+}
+
+// ReadAnnotation reads an N-Caltech101 annotation file
+func ReadAnnotation(path string) (Annotation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Annotation{}, err
+	}
+
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+
+	box, err := readPoints(r)
+	if err != nil {
+		return Annotation{}, err
+	}
+
+	contour, err := readPoints(r)
+	if err != nil {
+		return Annotation{}, err
+	}
+
+	return Annotation{Box: box, Contour: contour}, nil
+}
+
+// Bounds returns the smallest rectangle containing the bounding box
+func (a Annotation) Bounds() image.Rectangle {
+	if len(a.Box) == 0 {
+		return image.Rectangle{}
+	}
+
+	r := image.Rect(a.Box[0].X, a.Box[0].Y, a.Box[0].X+1, a.Box[0].Y+1)
+	for _, p := range a.Box[1:] {
+		r = r.Union(image.Rect(p.X, p.Y, p.X+1, p.Y+1))
+	}
+	return r
+}
+
+// Displace moves the annotation by the saccade offset at ts, undoing the correction Stabilize applies to events
+// recorded at ts
+func (a Annotation) Displace(ts int) Annotation {
+	corrected := Stabilize([]event.Event{{Ts: ts}})[0].Coords
+	dx, dy := -corrected.X, -corrected.Y
+
+	move := func(src []event.Point2D) []event.Point2D {
+		dst := make([]event.Point2D, len(src))
+		for i, p := range src {
+			dst[i] = event.Point2D{X: p.X + dx, Y: p.Y + dy}
+		}
+		return dst
+	}
+
+	return Annotation{Box: move(a.Box), Contour: move(a.Contour)}
+}
+
+// Crop keeps the events inside the bounding box of the annotation. If stabilized is false, events are raw and the
+// box follows the saccades, otherwise events are expected to be corrected with Stabilize. Events are not modified.
+func Crop(src []event.Event, a Annotation, stabilized bool) []event.Event {
+	dst := []event.Event{}
+	bounds := a.Bounds()
+
+	positions := src
+	if !stabilized {
+		positions = Stabilize(src)
+	}
+
+	for i, e := range src {
+		if image.Pt(positions[i].Coords.X, positions[i].Coords.Y).In(bounds) {
+			dst = append(dst, e)
+		}
+	}
+
+	return dst
+}
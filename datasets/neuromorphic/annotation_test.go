@@ -0,0 +1,89 @@
+package neuromorphic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+func TestReadAnnotation(t *testing.T) {
+	dir := t.TempDir()
+
+	b := &bytes.Buffer{}
+	binary.Write(b, binary.LittleEndian, []int16{2, 5, 10, 20, 40, 20, 40, 50, 10, 50, 10, 20})
+	binary.Write(b, binary.LittleEndian, []int16{2, 3, 15, 25, 30, 25, 20, 45})
+
+	path := AnnotationPath(filepath.Join(dir, "Caltech101_annotations"), "Caltech101/airplanes/image_0001.bin")
+	if want := filepath.Join(dir, "Caltech101_annotations", "airplanes", "annotation_0001.bin"); path != want {
+		t.Fatalf("AnnotationPath() = %v, want %v", path, want)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadAnnotation(path)
+	if err != nil {
+		t.Fatalf("ReadAnnotation() error = %v", err)
+	}
+
+	want := Annotation{
+		Box:     []event.Point2D{{X: 10, Y: 20}, {X: 40, Y: 20}, {X: 40, Y: 50}, {X: 10, Y: 50}, {X: 10, Y: 20}},
+		Contour: []event.Point2D{{X: 15, Y: 25}, {X: 30, Y: 25}, {X: 20, Y: 45}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadAnnotation() = %v, want %v", got, want)
+	}
+
+	if bounds := got.Bounds(); bounds != image.Rect(10, 20, 41, 51) {
+		t.Errorf("Annotation.Bounds() = %v", bounds)
+	}
+
+	if err := os.WriteFile(path, b.Bytes()[:30], 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadAnnotation(path); err == nil {
+		t.Errorf("ReadAnnotation() on a truncated file should return an error")
+	}
+}
+
+func TestCrop(t *testing.T) {
+	a := Annotation{Box: []event.Point2D{{X: 10, Y: 10}, {X: 20, Y: 10}, {X: 20, Y: 20}, {X: 10, Y: 20}, {X: 10, Y: 10}}}
+
+	// at 210ms the saccade offset is (7, 14)
+	src := []event.Event{
+		{Coords: event.Point2D{X: 10, Y: 10}, Ts: 0, P: 1},
+		{Coords: event.Point2D{X: 21, Y: 10}, Ts: 0, P: 1},
+		{Coords: event.Point2D{X: 27, Y: 34}, Ts: 210e3, P: 0},
+		{Coords: event.Point2D{X: 15, Y: 15}, Ts: 210e3, P: 0},
+	}
+
+	tests := []struct {
+		name       string
+		stabilized bool
+		want       []event.Event
+	}{
+		{name: "Test crop raw events", stabilized: false, want: []event.Event{src[0], src[2]}},
+		{name: "Test crop stabilized events", stabilized: true, want: []event.Event{src[0], src[3]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Crop(src, a, tt.stabilized); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Crop() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	displaced := a.Displace(210e3)
+	if displaced.Bounds() != image.Rect(17, 24, 28, 35) {
+		t.Errorf("Annotation.Displace() bounds = %v", displaced.Bounds())
+	}
+}
@@ -1,8 +1,12 @@
 package neuromorphic
 
 import (
+	"math"
+
 	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/datasets"
 	"github.com/ffardo/go-event-vision/format/atis"
+	"github.com/ffardo/go-event-vision/motion"
 )
 
 // NeuromorphicDataset implements DatasetReader interface for N-MNIST and N-Caltech100 datasets
@@ -24,53 +28,38 @@ func (n NeuromorphicDataset) Write(evCap event.EventCapture) error {
 	return atis.WriteEvents(evCap)
 }
 
-// Stabilize corrects saccadic motion
-func Stabilize(src []event.Event) []event.Event {
-	dst := []event.Event{}
-
-	ts := 0
-
-	s1, s2 := 0, 0
-
-	for i := 0; i < len(src) && ts <= 105e3; i++ {
-		ts = src[i].Ts
-		s1 = i
-	}
+// NMNISTIndex enumerates the samples of N-MNIST. root must contain the Train and Test folders, each with one folder per digit.
+func NMNISTIndex(root string) (datasets.Index, error) {
+	return datasets.DiscoverSplits(root, map[string]string{"Train": "train", "Test": "test"}, ".bin")
+}
 
-	for i := s1; i < len(src) && ts <= 210e3; i++ {
-		ts = src[i].Ts
-		s2 = i
+// NCaltech101Index enumerates the samples of N-Caltech101. root must contain one folder per category.
+// N-Caltech101 has no predefined splits, so StratifiedSplit or KFold should be used.
+func NCaltech101Index(root string) (datasets.Index, error) {
+	samples, err := datasets.Discover(root, ".bin")
+	if err != nil {
+		return datasets.Index{}, err
 	}
-
-	dst = append(
-		dst, correctSaccade(src[0:s1], func(e event.Event) event.Event {
-			e.Coords.X -= int(3.5 * float64(e.Ts) / float64(105e3))
-			e.Coords.Y -= int(3.5 * float64(e.Ts) / float64(105e3))
-			return e
-		})...,
-	)
-	dst = append(
-		dst, correctSaccade(src[s1:s2], func(e event.Event) event.Event {
-			e.Coords.X -= int(3.5 + 3.5*(float64(e.Ts)-105e3)/105e3)
-			e.Coords.Y -= int(7 + 7*(float64(e.Ts)-105e3)/105e3)
-			return e
-		})...,
-	)
-	dst = append(
-		dst, correctSaccade(src[s2:], func(e event.Event) event.Event {
-			e.Coords.X -= int(7 + 7*(float64(e.Ts)-210e3)/105e3)
-			return e
-		})...,
-	)
-
-	return dst
+	return datasets.NewIndex(root, samples), nil
 }
 
-func correctSaccade(ev []event.Event, correction func(event.Event) event.Event) []event.Event {
-	c := make([]event.Event, len(ev))
+// saccades is the legacy correction of Stabilize, where the motion.NMNISTSaccades displacement is truncated to
+// whole pixels, extrapolating the third saccade after 315 ms
+var saccades = motion.Func(func(ts int) (dx, dy float64) {
+	t := float64(ts)
 
-	for i, ev := range ev {
-		c[i] = correction(ev)
+	switch {
+	case t <= 105e3:
+		return math.Trunc(3.5 * t / 105e3), math.Trunc(3.5 * t / 105e3)
+	case t <= 210e3:
+		return math.Trunc(3.5 + 3.5*(t-105e3)/105e3), math.Trunc(7 + 7*(t-105e3)/105e3)
 	}
-	return c
+	return math.Trunc(7 + 7*(t-210e3)/105e3), 0
+})
+
+// Stabilize corrects saccadic motion with the motion.NMNISTSaccades profile, subtracting the displacement
+// truncated to whole pixels. Each event is corrected with the saccade of its timestamp.
+// Use motion.Compensator with motion.NMNISTSaccades for sub-pixel rounding or clipping to the sensor.
+func Stabilize(src []event.Event) []event.Event {
+	return motion.Compensator{Model: saccades, Rounding: motion.Truncate}.Apply(src)
 }
@@ -1,6 +1,8 @@
 package neuromorphic
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -47,3 +49,59 @@ func TestNeuromorphicDataset_Read(t *testing.T) {
 		})
 	}
 }
+
+func TestNMNISTIndex(t *testing.T) {
+	root := t.TempDir()
+	for _, f := range []string{"Train/0/00002.bin", "Train/1/00003.bin", "Test/0/00001.bin"} {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	idx, err := NMNISTIndex(root)
+	if err != nil {
+		t.Fatalf("NMNISTIndex() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(idx.Classes, []string{"0", "1"}) || len(idx.Split("train").Samples) != 2 || len(idx.Split("test").Samples) != 1 {
+		t.Errorf("NMNISTIndex() = %v", idx)
+	}
+}
+
+func TestStabilize(t *testing.T) {
+	tests := []struct {
+		name string
+		src  []event.Event
+		want []event.Event
+	}{
+		{name: "Test empty stream", src: []event.Event{}, want: []event.Event{}},
+		{
+			name: "Test saccades",
+			src: []event.Event{
+				{Coords: event.Point2D{X: 10, Y: 10}, Ts: 0, P: 1},
+				{Coords: event.Point2D{X: 10, Y: 10}, Ts: 52500, P: 1},
+				{Coords: event.Point2D{X: 10, Y: 10}, Ts: 105e3, P: 1},
+				{Coords: event.Point2D{X: 10, Y: 20}, Ts: 210e3, P: 0},
+				{Coords: event.Point2D{X: 10, Y: 10}, Ts: 315e3, P: 1},
+			},
+			want: []event.Event{
+				{Coords: event.Point2D{X: 10, Y: 10}, Ts: 0, P: 1},
+				{Coords: event.Point2D{X: 9, Y: 9}, Ts: 52500, P: 1},
+				{Coords: event.Point2D{X: 7, Y: 7}, Ts: 105e3, P: 1},
+				{Coords: event.Point2D{X: 3, Y: 6}, Ts: 210e3, P: 0},
+				{Coords: event.Point2D{X: -4, Y: 10}, Ts: 315e3, P: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Stabilize(tt.src); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Stabilize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
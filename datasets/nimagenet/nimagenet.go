@@ -0,0 +1,149 @@
+package nimagenet
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/datasets"
+	"github.com/ffardo/go-event-vision/format"
+	"github.com/ffardo/go-event-vision/format/numpy"
+)
+
+const (
+	// Width of the Prophesee Gen3 sensor used to record N-ImageNet
+	Width = 640
+	// Height of the Prophesee Gen3 sensor used to record N-ImageNet
+	Height = 480
+
+	eventDataKey = "event_data"
+)
+
+// Nimagenet implements DatasetReader interface for N-ImageNet samples.
+// Samples are compressed .npz archives holding an event_data structured array with x, y, t and p fields.
+type Nimagenet struct {
+	FilePath string
+}
+
+// Read event capture for an entry in the dataset
+func (n Nimagenet) Read() (event.EventCapture, error) {
+	arrays, err := numpy.ReadNpz(n.FilePath)
+	if err != nil {
+		return event.EventCapture{}, err
+	}
+
+	data, ok := arrays[eventDataKey]
+	if !ok {
+		return event.EventCapture{}, errors.New("Missing event_data array")
+	}
+
+	columns := make([][]int64, 4)
+	for i, name := range []string{"x", "y", "t", "p"} {
+		columns[i], err = data.Ints(name)
+		if err != nil {
+			return event.EventCapture{}, err
+		}
+	}
+
+	ev := make([]event.Event, data.Len())
+	for i := range ev {
+		ev[i] = event.Event{
+			Coords: event.Point2D{X: int(columns[0][i]), Y: int(columns[1][i])},
+			Ts:     int(columns[2][i]),
+			P:      int(columns[3][i]),
+		}
+	}
+
+	return event.EventCapture{Events: ev, Width: Width, Height: Height}, nil
+}
+
+// checkEvent reports why ev cannot be written to the event_data fields
+func checkEvent(ev event.Event) string {
+	switch {
+	case ev.Coords.X < 0 || ev.Coords.X > math.MaxUint16:
+		return fmt.Sprintf("x %d does not fit in 16 bits", ev.Coords.X)
+	case ev.Coords.Y < 0 || ev.Coords.Y > math.MaxUint16:
+		return fmt.Sprintf("y %d does not fit in 16 bits", ev.Coords.Y)
+	case ev.P != 0 && ev.P != 1:
+		return fmt.Sprintf("polarity %d is not boolean", ev.P)
+	}
+	return ""
+}
+
+// Write capture to a dataset. Should be used only for data augmentation.
+// Events which do not fit the event_data fields are reported as format.ErrOutOfRange, at the offset of the event
+// in the array data.
+func (n Nimagenet) Write(evCap event.EventCapture) error {
+	fields := []numpy.Field{
+		{Name: "x", Descr: "<u2"},
+		{Name: "y", Descr: "<u2"},
+		{Name: "t", Descr: "<i8"},
+		{Name: "p", Descr: "|b1"},
+	}
+	data, err := numpy.NewArray(fields, len(evCap.Events))
+	if err != nil {
+		return err
+	}
+
+	const itemSize = 2 + 2 + 8 + 1
+	for i, ev := range evCap.Events {
+		if detail := checkEvent(ev); detail != "" {
+			return format.NewError(format.ErrOutOfRange, int64(i*itemSize), detail)
+		}
+		for j, v := range []int{ev.Coords.X, ev.Coords.Y, ev.Ts, ev.P} {
+			if err := data.SetInt(fields[j].Name, i, int64(v)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return numpy.WriteNpz(n.FilePath, map[string]*numpy.Array{eventDataKey: data})
+}
+
+// ReadClassList reads a list of WordNet IDs, one per line, such as the class list of mini N-ImageNet.
+// Anything after the first whitespace of a line, such as a human readable class name, is ignored.
+func ReadClassList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	classes := []string{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if fields := strings.Fields(s.Text()); len(fields) > 0 {
+			classes = append(classes, fields[0])
+		}
+	}
+	return classes, s.Err()
+}
+
+// Index enumerates the samples of N-ImageNet. root must contain the extracted_train and extracted_val folders, each
+// with one folder per WordNet ID, which is used as label. If classes is not empty, only samples of the listed
+// classes are enumerated, which allows building mini N-ImageNet.
+func Index(root string, classes []string) (datasets.Index, error) {
+	idx, err := datasets.DiscoverSplits(root, map[string]string{"extracted_train": "train", "extracted_val": "val"}, ".npz")
+	if err != nil || len(classes) == 0 {
+		return idx, err
+	}
+
+	keep := map[string]bool{}
+	for _, c := range classes {
+		keep[c] = true
+	}
+
+	samples := []datasets.Sample{}
+	for _, s := range idx.Samples {
+		if keep[s.Label] {
+			samples = append(samples, s)
+		}
+	}
+
+	return datasets.NewIndex(root, samples), nil
+}
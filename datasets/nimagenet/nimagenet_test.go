@@ -0,0 +1,94 @@
+package nimagenet
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/format"
+)
+
+func TestNimagenet_Read(t *testing.T) {
+	dir := t.TempDir()
+	n := Nimagenet{FilePath: filepath.Join(dir, "n01440764_10026.npz")}
+
+	evCap := event.EventCapture{
+		Events: []event.Event{
+			{Coords: event.Point2D{X: 10, Y: 30}, Ts: 937, P: 1},
+			{Coords: event.Point2D{X: 639, Y: 479}, Ts: 1030, P: 0},
+			{Coords: event.Point2D{X: 12, Y: 27}, Ts: 1 << 40, P: 1},
+		},
+		Width:  Width,
+		Height: Height,
+	}
+
+	if err := n.Write(evCap); err != nil {
+		t.Fatalf("Nimagenet.Write() error = %v", err)
+	}
+
+	got, err := n.Read()
+	if err != nil {
+		t.Fatalf("Nimagenet.Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, evCap) {
+		t.Errorf("Nimagenet.Read() = %v, want %v", got, evCap)
+	}
+
+	if _, err := (Nimagenet{FilePath: filepath.Join(dir, "missing.npz")}).Read(); err == nil {
+		t.Errorf("Nimagenet.Read() on a missing file should return an error")
+	}
+
+	invalid := []event.Event{
+		{Coords: event.Point2D{X: -1, Y: 30}, Ts: 937, P: 1},
+		{Coords: event.Point2D{X: 10, Y: 1 << 16}, Ts: 937, P: 1},
+		{Coords: event.Point2D{X: 10, Y: 30}, Ts: 937, P: -1},
+	}
+	for _, ev := range invalid {
+		events := append(append([]event.Event{}, evCap.Events...), ev)
+		var fErr *format.Error
+		if err := n.Write(event.EventCapture{Events: events}); !errors.Is(err, format.ErrOutOfRange) || !errors.As(err, &fErr) || fErr.Offset != 3*13 {
+			t.Errorf("Nimagenet.Write(%v) error = %v, want %v at offset %v", ev, err, format.ErrOutOfRange, 3*13)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	root := t.TempDir()
+	files := []string{
+		"extracted_train/n01440764/n01440764_1.npz",
+		"extracted_train/n01443537/n01443537_1.npz",
+		"extracted_val/n01440764/ILSVRC2012_val_1.npz",
+	}
+	for _, f := range files {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listPath := filepath.Join(root, "mini.txt")
+	if err := os.WriteFile(listPath, []byte("n01440764 tench\n\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	classes, err := ReadClassList(listPath)
+	if err != nil || !reflect.DeepEqual(classes, []string{"n01440764"}) {
+		t.Fatalf("ReadClassList() = %v, error = %v", classes, err)
+	}
+
+	full, err := Index(root, nil)
+	if err != nil || len(full.Samples) != 3 || len(full.Classes) != 2 {
+		t.Errorf("Index() = %v, error = %v", full, err)
+	}
+
+	mini, err := Index(root, classes)
+	if err != nil || len(mini.Split("train").Samples) != 1 || len(mini.Split("val").Samples) != 1 || !reflect.DeepEqual(mini.Classes, classes) {
+		t.Errorf("Index() with class list = %v, error = %v", mini, err)
+	}
+}
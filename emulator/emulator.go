@@ -0,0 +1,202 @@
+// emulator generates synthetic event data from conventional intensity frames, in the spirit of ESIM and v2e.
+// Each pixel memorizes the log intensity at its last event and emits an ON (OFF) event whenever the log intensity,
+// linearly interpolated between frames, rises (falls) by more than the pixel's contrast threshold.
+package emulator
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+// Frame represents an intensity image captured at timestamp Ts, usually expressed in microsseconds
+type Frame struct {
+	Image image.Image
+	Ts    int
+}
+
+// Config holds the parameters of the emulated sensor
+type Config struct {
+	PositiveThreshold float64 // ON contrast threshold in log intensity units
+	NegativeThreshold float64 // OFF contrast threshold in log intensity units
+	ThresholdSigma    float64 // standard deviation of the per pixel threshold mismatch
+	MinThreshold      float64 // thresholds drawn below this value are clipped to it
+	RefractoryPeriod  int     // minimum interval between two events of the same pixel
+	LogEpsilon        float64 // offset added to the normalized intensity before taking the logarithm
+	Seed              int64   // seed for the threshold mismatch
+}
+
+// DefaultConfig returns a configuration with typical DVS parameters
+func DefaultConfig() Config {
+	return Config{
+		PositiveThreshold: 0.2,
+		NegativeThreshold: 0.2,
+		ThresholdSigma:    0.03,
+		MinThreshold:      0.01,
+		RefractoryPeriod:  0,
+		LogEpsilon:        1e-3,
+	}
+}
+
+// Emulator converts a sequence of frames to events. Frames must be fed in chronological order.
+type Emulator struct {
+	config Config
+	width  int
+	height int
+
+	posTh     []float64
+	negTh     []float64
+	reference []float64 // log intensity memorized at the last crossing
+	lastLog   []float64 // log intensity of the previous frame
+	lastEvent []int     // timestamp of the last emitted event
+	hasEvent  []bool
+	lastTs    int
+	hasFrame  bool
+}
+
+// New creates an Emulator for frames of width x height pixels
+func New(width, height int, config Config) (*Emulator, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("Invalid frame size")
+	}
+	if config.PositiveThreshold <= 0 || config.NegativeThreshold <= 0 {
+		return nil, errors.New("Contrast thresholds must be positive")
+	}
+
+	n := width * height
+	e := &Emulator{
+		config:    config,
+		width:     width,
+		height:    height,
+		posTh:     make([]float64, n),
+		negTh:     make([]float64, n),
+		reference: make([]float64, n),
+		lastLog:   make([]float64, n),
+		lastEvent: make([]int, n),
+		hasEvent:  make([]bool, n),
+	}
+
+	random := rand.New(rand.NewSource(config.Seed))
+	for i := 0; i < n; i++ {
+		e.posTh[i] = e.threshold(config.PositiveThreshold, random)
+		e.negTh[i] = e.threshold(config.NegativeThreshold, random)
+	}
+
+	return e, nil
+}
+
+func (e *Emulator) threshold(mean float64, random *rand.Rand) float64 {
+	th := mean + e.config.ThresholdSigma*random.NormFloat64()
+	return math.Max(th, math.Max(e.config.MinThreshold, 1e-6))
+}
+
+func (e *Emulator) logIntensity(img image.Image) ([]float64, error) {
+	b := img.Bounds()
+	if b.Dx() != e.width || b.Dy() != e.height {
+		return nil, errors.New("Frame size does not match emulator size")
+	}
+
+	l := make([]float64, e.width*e.height)
+	for y := 0; y < e.height; y++ {
+		for x := 0; x < e.width; x++ {
+			g := color.Gray16Model.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray16)
+			l[y*e.width+x] = math.Log(float64(g.Y)/65535.0 + e.config.LogEpsilon)
+		}
+	}
+	return l, nil
+}
+
+func (e *Emulator) emit(dst []event.Event, idx, ts, p int) []event.Event {
+	if e.hasEvent[idx] && ts-e.lastEvent[idx] < e.config.RefractoryPeriod {
+		return dst
+	}
+
+	e.hasEvent[idx] = true
+	e.lastEvent[idx] = ts
+	return append(dst, event.Event{
+		Coords: event.Point2D{X: idx % e.width, Y: idx / e.width},
+		Ts:     ts,
+		P:      p,
+	})
+}
+
+// Feed processes the next frame and returns the events generated since the previous frame, sorted by timestamp.
+// The first frame only initializes the memorized log intensity of each pixel and generates no events.
+func (e *Emulator) Feed(frame Frame) ([]event.Event, error) {
+	l, err := e.logIntensity(frame.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	dst := []event.Event{}
+
+	if !e.hasFrame {
+		copy(e.reference, l)
+		copy(e.lastLog, l)
+		e.lastTs = frame.Ts
+		e.hasFrame = true
+		return dst, nil
+	}
+
+	if frame.Ts <= e.lastTs {
+		return nil, errors.New("Frames must be fed in chronological order")
+	}
+
+	t0 := float64(e.lastTs)
+	dt := float64(frame.Ts - e.lastTs)
+
+	for i, l1 := range l {
+		l0 := e.lastLog[i]
+		dl := l1 - l0
+
+		// Log intensity is linearly interpolated between frames, so each crossing of the memorized
+		// reference level happens at a time proportional to the interpolated change
+		for l1-e.reference[i] >= e.posTh[i] {
+			e.reference[i] += e.posTh[i]
+			ts := int(t0 + dt*(e.reference[i]-l0)/dl)
+			dst = e.emit(dst, i, ts, 1)
+		}
+
+		for e.reference[i]-l1 >= e.negTh[i] {
+			e.reference[i] -= e.negTh[i]
+			ts := int(t0 + dt*(e.reference[i]-l0)/dl)
+			dst = e.emit(dst, i, ts, 0)
+		}
+
+		e.lastLog[i] = l1
+	}
+
+	e.lastTs = frame.Ts
+
+	sort.SliceStable(dst, func(i, j int) bool { return dst[i].Ts < dst[j].Ts })
+	return dst, nil
+}
+
+// Emulate converts a chronologically ordered sequence of frames into an event capture with the size of the frames
+func Emulate(frames []Frame, config Config) (event.EventCapture, error) {
+	if len(frames) == 0 {
+		return event.EventCapture{}, errors.New("No frames to emulate")
+	}
+
+	b := frames[0].Image.Bounds()
+	e, err := New(b.Dx(), b.Dy(), config)
+	if err != nil {
+		return event.EventCapture{}, err
+	}
+
+	ev := []event.Event{}
+	for _, f := range frames {
+		n, err := e.Feed(f)
+		if err != nil {
+			return event.EventCapture{}, err
+		}
+		ev = append(ev, n...)
+	}
+
+	return event.EventCapture{Events: ev, Width: b.Dx(), Height: b.Dy()}, nil
+}
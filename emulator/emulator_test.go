@@ -0,0 +1,103 @@
+package emulator
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+func grayFrame(width, height int, value uint8, ts int) Frame {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetGray(x, y, color.Gray{Y: value})
+		}
+	}
+	return Frame{Image: img, Ts: ts}
+}
+
+func countPolarity(ev []event.Event, p int) int {
+	c := 0
+	for _, e := range ev {
+		if e.P == p {
+			c++
+		}
+	}
+	return c
+}
+
+func TestEmulate(t *testing.T) {
+	config := Config{PositiveThreshold: 0.2, NegativeThreshold: 0.2, LogEpsilon: 1e-3}
+
+	tests := []struct {
+		name   string
+		frames []Frame
+		config Config
+		wantOn int
+		wantOf int
+	}{
+		{
+			name:   "Test constant frames generate no events",
+			frames: []Frame{grayFrame(2, 2, 100, 0), grayFrame(2, 2, 100, 1000)},
+			config: config,
+		},
+		{
+			// ln(200/50) / 0.2 = 6.93 crossings per pixel
+			name:   "Test brightness increase generates ON events",
+			frames: []Frame{grayFrame(2, 2, 50, 0), grayFrame(2, 2, 200, 1000)},
+			config: config,
+			wantOn: 24,
+		},
+		{
+			name:   "Test brightness decrease generates OFF events",
+			frames: []Frame{grayFrame(2, 2, 200, 0), grayFrame(2, 2, 50, 1000)},
+			config: config,
+			wantOf: 24,
+		},
+		{
+			name:   "Test refractory period suppresses events",
+			frames: []Frame{grayFrame(2, 2, 50, 0), grayFrame(2, 2, 200, 1000)},
+			config: Config{PositiveThreshold: 0.2, NegativeThreshold: 0.2, LogEpsilon: 1e-3, RefractoryPeriod: 1000},
+			wantOn: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Emulate(tt.frames, tt.config)
+			if err != nil {
+				t.Fatalf("Emulate() error = %v", err)
+			}
+			if got.Width != 2 || got.Height != 2 {
+				t.Errorf("Emulate() size = %dx%d, want 2x2", got.Width, got.Height)
+			}
+			if on, off := countPolarity(got.Events, 1), countPolarity(got.Events, 0); on != tt.wantOn || off != tt.wantOf {
+				t.Errorf("Emulate() ON = %d, OFF = %d, want ON = %d, OFF = %d", on, off, tt.wantOn, tt.wantOf)
+			}
+			for i, e := range got.Events {
+				if e.Ts < 0 || e.Ts > 1000 || (i > 0 && e.Ts < got.Events[i-1].Ts) {
+					t.Fatalf("Emulate() events should be sorted and within frame interval, got %v", got.Events)
+				}
+			}
+		})
+	}
+}
+
+func TestEmulateErrors(t *testing.T) {
+	if _, err := Emulate([]Frame{}, DefaultConfig()); err == nil {
+		t.Errorf("Emulate() without frames should return an error")
+	}
+
+	if _, err := Emulate([]Frame{grayFrame(2, 2, 0, 0)}, Config{}); err == nil {
+		t.Errorf("Emulate() with zero thresholds should return an error")
+	}
+
+	if _, err := Emulate([]Frame{grayFrame(2, 2, 0, 10), grayFrame(2, 2, 0, 5)}, DefaultConfig()); err == nil {
+		t.Errorf("Emulate() with frames out of order should return an error")
+	}
+
+	if _, err := Emulate([]Frame{grayFrame(2, 2, 0, 0), grayFrame(3, 2, 0, 5)}, DefaultConfig()); err == nil {
+		t.Errorf("Emulate() with frames of different sizes should return an error")
+	}
+}
@@ -0,0 +1,162 @@
+// evaluation measures how well denoising filters separate signal from noise events.
+// Inputs are labeled event streams, such as the ones produced by noise.Generator.ApplyAdditiveLabeled,
+// where noisy[i] is true when src[i] is a noise event.
+package evaluation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/filter"
+)
+
+// Result holds the outcome of a filter applied to a labeled event stream.
+// Signal events kept by the filter are true positives and noise events kept are false positives.
+type Result struct {
+	Name       string  `json:"name"`       // name of the evaluated filter
+	Parameter  float64 `json:"parameter"`  // value of the swept parameter
+	Signal     int     `json:"signal"`     // signal events in the input
+	Noise      int     `json:"noise"`      // noise events in the input
+	SignalKept int     `json:"signalKept"` // signal events allowed through the filter
+	NoiseKept  int     `json:"noiseKept"`  // noise events allowed through the filter
+	TPR        float64 `json:"tpr"`        // true positive rate, fraction of signal kept
+	FPR        float64 `json:"fpr"`        // false positive rate, fraction of noise kept
+	Precision  float64 `json:"precision"`  // fraction of the output which is signal
+	SR         float64 `json:"sr"`         // signal ratio, fraction of signal kept
+	NR         float64 `json:"nr"`         // noise ratio, fraction of noise removed
+	DA         float64 `json:"da"`         // denoising accuracy, mean of SR and NR
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0.0
+	}
+	return float64(a) / float64(b)
+}
+
+// Evaluate compares the output of a filter with its labeled input.
+// Filtered events are matched with source events by value. When identical signal and noise events exist,
+// matches are attributed to signal first.
+func Evaluate(src []event.Event, noisy []bool, filtered []event.Event) (Result, error) {
+	if len(src) != len(noisy) {
+		return Result{}, errors.New("Source events and labels must have the same length")
+	}
+
+	r := Result{}
+	available := make(map[event.Event][2]int)
+
+	for i, ev := range src {
+		c := available[ev]
+		if noisy[i] {
+			c[1]++
+			r.Noise++
+		} else {
+			c[0]++
+			r.Signal++
+		}
+		available[ev] = c
+	}
+
+	for _, ev := range filtered {
+		c := available[ev]
+		if c[0] > 0 {
+			c[0]--
+			r.SignalKept++
+		} else if c[1] > 0 {
+			c[1]--
+			r.NoiseKept++
+		} else {
+			return Result{}, errors.New("Filtered events must be a subset of source events")
+		}
+		available[ev] = c
+	}
+
+	r.TPR = ratio(r.SignalKept, r.Signal)
+	r.FPR = ratio(r.NoiseKept, r.Noise)
+	r.Precision = ratio(r.SignalKept, r.SignalKept+r.NoiseKept)
+	r.SR = r.TPR
+	r.NR = 1.0 - r.FPR
+	r.DA = (r.SR + r.NR) / 2.0
+
+	return r, nil
+}
+
+// Sweep evaluates the filter built by factory for each parameter value on the same labeled input
+func Sweep(name string, src []event.Event, noisy []bool, params []float64, factory func(float64) filter.Denoiser) ([]Result, error) {
+	results := make([]Result, 0, len(params))
+
+	for _, p := range params {
+		r, err := Evaluate(src, noisy, factory(p).Apply(src))
+		if err != nil {
+			return nil, err
+		}
+		r.Name = name
+		r.Parameter = p
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+// AUC computes the area under the ROC curve traced by results using the trapezoidal rule.
+// The curve is closed with the (0,0) and (1,1) points, which correspond to removing and keeping all events.
+func AUC(results []Result) float64 {
+	type point struct{ fpr, tpr float64 }
+
+	pts := []point{{0, 0}, {1, 1}}
+	for _, r := range results {
+		pts = append(pts, point{r.FPR, r.TPR})
+	}
+
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i].fpr == pts[j].fpr {
+			return pts[i].tpr < pts[j].tpr
+		}
+		return pts[i].fpr < pts[j].fpr
+	})
+
+	area := 0.0
+	for i := 1; i < len(pts); i++ {
+		area += (pts[i].fpr - pts[i-1].fpr) * (pts[i].tpr + pts[i-1].tpr) / 2.0
+	}
+	return area
+}
+
+// WriteCSV writes results to w in CSV format with a header row
+func WriteCSV(w io.Writer, results []Result) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write([]string{"name", "parameter", "signal", "noise", "signal_kept", "noise_kept", "tpr", "fpr", "precision", "sr", "nr", "da"})
+	if err != nil {
+		return err
+	}
+
+	f := func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+
+	for _, r := range results {
+		err = cw.Write([]string{
+			r.Name, f(r.Parameter),
+			strconv.Itoa(r.Signal), strconv.Itoa(r.Noise), strconv.Itoa(r.SignalKept), strconv.Itoa(r.NoiseKept),
+			f(r.TPR), f(r.FPR), f(r.Precision), f(r.SR), f(r.NR), f(r.DA),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes results to w as a JSON array
+func WriteJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(results)
+}
@@ -0,0 +1,129 @@
+package evaluation
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/filter"
+)
+
+var src = []event.Event{
+	{Coords: event.Point2D{X: 0, Y: 0}, Ts: 100, P: 1},
+	{Coords: event.Point2D{X: 1, Y: 0}, Ts: 200, P: 1},
+	{Coords: event.Point2D{X: 6, Y: 6}, Ts: 250, P: 0},
+	{Coords: event.Point2D{X: 2, Y: 0}, Ts: 300, P: 1},
+	{Coords: event.Point2D{X: 3, Y: 7}, Ts: 350, P: 0},
+}
+
+var noisy = []bool{false, false, true, false, true}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		filtered []event.Event
+		want     Result
+		wantErr  bool
+	}{
+		{
+			name:     "Test keeping all events",
+			filtered: src,
+			want:     Result{Signal: 3, Noise: 2, SignalKept: 3, NoiseKept: 2, TPR: 1, FPR: 1, Precision: 0.6, SR: 1, NR: 0, DA: 0.5},
+		},
+		{
+			name:     "Test perfect filter",
+			filtered: []event.Event{src[0], src[1], src[3]},
+			want:     Result{Signal: 3, Noise: 2, SignalKept: 3, TPR: 1, Precision: 1, SR: 1, NR: 1, DA: 1},
+		},
+		{
+			name:     "Test removing all events",
+			filtered: []event.Event{},
+			want:     Result{Signal: 3, Noise: 2, NR: 1, DA: 0.5},
+		},
+		{
+			name:     "Test filtered events not in source",
+			filtered: []event.Event{{Coords: event.Point2D{X: 9, Y: 9}, Ts: 1, P: 1}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Evaluate(src, noisy, tt.filtered)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Evaluate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Evaluate(src, noisy[:2], src); err == nil {
+		t.Errorf("Evaluate() with labels of different length should return an error")
+	}
+}
+
+func TestSweep(t *testing.T) {
+	results, err := Sweep("stcf", src, noisy, []float64{50, 150}, func(p float64) filter.Denoiser {
+		return filter.STCF{Width: 8, Height: 8, Radius: 1, UsTime: int(p), K: 1}
+	})
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+
+	if len(results) != 2 || results[0].Name != "stcf" || results[1].Parameter != 150 {
+		t.Fatalf("Sweep() should return one named result per parameter, got %v", results)
+	}
+
+	if results[0].SignalKept != 0 || results[1].SignalKept != 2 || results[1].NoiseKept != 0 {
+		t.Errorf("Sweep() = %v, want 0 and 2 signal events kept without noise", results)
+	}
+}
+
+func TestAUC(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []Result
+		want    float64
+	}{
+		{name: "Test without results", results: []Result{}, want: 0.5},
+		{name: "Test perfect classifier", results: []Result{{TPR: 1, FPR: 0}}, want: 1.0},
+		{name: "Test single operating point", results: []Result{{TPR: 0.8, FPR: 0.2}}, want: 0.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AUC(tt.results); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("AUC() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	results := []Result{{Name: "ba", Parameter: 1000, Signal: 3, SignalKept: 3, TPR: 1, SR: 1, NR: 1, DA: 1}}
+
+	csvOut := &bytes.Buffer{}
+	if err := WriteCSV(csvOut, results); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != 2 || lines[1] != "ba,1000,3,0,3,0,1,0,0,1,1,1" {
+		t.Errorf("WriteCSV() = %q", csvOut.String())
+	}
+
+	jsonOut := &bytes.Buffer{}
+	if err := WriteJSON(jsonOut, results); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	decoded := []Result{}
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil || !reflect.DeepEqual(decoded, results) {
+		t.Errorf("WriteJSON() = %s, error = %v", jsonOut.String(), err)
+	}
+}
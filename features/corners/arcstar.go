@@ -0,0 +1,97 @@
+package corners
+
+import (
+	"github.com/ffardo/go-event-vision"
+)
+
+// ArcStar implements the Arc* corner detector. Starting from the most recent pixel of each circle of eFAST, an arc
+// is grown toward the most recent neighbor, and an event is a corner when the arc, or its complement, has 3 to 6
+// pixels on the circle of radius 3 and 4 to 8 pixels on the circle of radius 4.
+type ArcStar struct {
+	Width    int
+	Height   int
+	FilterUs int // minimum time between events of a pixel updating the SAE, as in the original work. 0 disables the filter
+}
+
+// arcStar grows the arc of the most recent elements of the circle and checks its length
+func arcStar(ts []int, minLen, maxLen int) bool {
+	n := len(ts)
+
+	newest := 0
+	for i := 1; i < n; i++ {
+		if ts[i] > ts[newest] {
+			newest = i
+		}
+	}
+
+	segmentMin := ts[newest]
+	right, left := (newest+1)%n, (newest-1+n)%n
+	rightVal, leftVal := ts[right], ts[left]
+	rightMin, leftMin := rightVal, leftVal
+
+	// advance moves the arc end toward the most recent neighbor
+	advance := func() {
+		if rightVal > leftVal {
+			right = (right + 1) % n
+			rightVal = ts[right]
+			if rightVal < rightMin {
+				rightMin = rightVal
+			}
+		} else {
+			left = (left - 1 + n) % n
+			leftVal = ts[left]
+			if leftVal < leftMin {
+				leftMin = leftVal
+			}
+		}
+	}
+
+	// the arc always grows up to the minimum length
+	for i := 1; i < minLen; i++ {
+		if rightVal > leftVal {
+			if rightMin < segmentMin {
+				segmentMin = rightMin
+			}
+		} else if leftMin < segmentMin {
+			segmentMin = leftMin
+		}
+		advance()
+	}
+
+	// then only while the next element is at least as recent as the arc
+	size := minLen
+	for i := minLen; i < n; i++ {
+		next, nextMin := leftVal, leftMin
+		if rightVal > leftVal {
+			next, nextMin = rightVal, rightMin
+		}
+		if next >= segmentMin {
+			size = i + 1
+			if nextMin < segmentMin {
+				segmentMin = nextMin
+			}
+		}
+		advance()
+	}
+
+	return size <= maxLen || (size >= n-maxLen && size <= n-minLen)
+}
+
+// Detect keeps the events of src classified as corners
+func (a ArcStar) Detect(src []event.Event) []event.Event {
+	s := newSurface(a.Width, a.Height, a.FilterUs)
+	ts := []int{}
+
+	return detect(src, func(ev event.Event) bool {
+		if !s.update(ev) || !s.inside(ev.Coords.X, ev.Coords.Y, circleRadius) {
+			return false
+		}
+
+		ts = s.circle(ev, innerCircle, ts)
+		if !arcStar(ts, 3, 6) {
+			return false
+		}
+		ts = s.circle(ev, outerCircle, ts)
+		return arcStar(ts, 4, 8)
+	})
+}
@@ -0,0 +1,103 @@
+/*
+corners implements event-based corner detectors working on a surface of active events (SAE) per polarity:
+
+	EFast   eFAST, by Mueggler et al., "Fast Event-based Corner Detection" (2017)
+	ArcStar Arc*, by Alzugaray and Chli, "Asynchronous Corner Detection and Tracking for Event Cameras" (2018)
+	EHarris eHarris, by Vasco et al., "Fast event-based Harris corner detection exploiting the advantages of event-driven cameras" (2016)
+
+Each detector processes events in order and keeps the ones classified as corners, so the output can be further
+processed with the filter package or rendered with the render package.
+*/
+package corners
+
+import (
+	"github.com/ffardo/go-event-vision"
+)
+
+// Detector keeps the events of a stream classified as corners
+type Detector interface {
+	Detect(src []event.Event) []event.Event
+}
+
+// offsets of the circles of radius 3 and 4 used by eFAST and Arc*, in clockwise order
+var (
+	innerCircle = []event.Point2D{
+		{X: 0, Y: 3}, {X: 1, Y: 3}, {X: 2, Y: 2}, {X: 3, Y: 1}, {X: 3, Y: 0}, {X: 3, Y: -1}, {X: 2, Y: -2}, {X: 1, Y: -3},
+		{X: 0, Y: -3}, {X: -1, Y: -3}, {X: -2, Y: -2}, {X: -3, Y: -1}, {X: -3, Y: 0}, {X: -3, Y: 1}, {X: -2, Y: 2}, {X: -1, Y: 3},
+	}
+	outerCircle = []event.Point2D{
+		{X: 0, Y: 4}, {X: 1, Y: 4}, {X: 2, Y: 3}, {X: 3, Y: 2}, {X: 4, Y: 1}, {X: 4, Y: 0}, {X: 4, Y: -1}, {X: 3, Y: -2}, {X: 2, Y: -3}, {X: 1, Y: -4},
+		{X: 0, Y: -4}, {X: -1, Y: -4}, {X: -2, Y: -3}, {X: -3, Y: -2}, {X: -4, Y: -1}, {X: -4, Y: 0}, {X: -4, Y: 1}, {X: -3, Y: 2}, {X: -2, Y: 3}, {X: -1, Y: 4},
+	}
+)
+
+const (
+	// circleRadius is the margin of sensor border pixels where eFAST and Arc* cannot classify events
+	circleRadius = 4
+	// unset is the timestamp of SAE pixels without events
+	unset = -1 << 31
+)
+
+// surface is a SAE per polarity. With a filter, events at a pixel are discarded if the previous event of the
+// same polarity at that pixel is more recent than filterUs, which suppresses bursts of events of a single edge.
+type surface struct {
+	width, height int
+	filterUs      int
+	sae           [2][]int
+	latest        [2][]int
+}
+
+func newSurface(width, height, filterUs int) *surface {
+	s := &surface{width: width, height: height, filterUs: filterUs}
+	for p := range s.sae {
+		s.sae[p] = make([]int, width*height)
+		s.latest[p] = make([]int, width*height)
+		for i := range s.sae[p] {
+			s.sae[p][i] = unset
+			s.latest[p][i] = unset
+		}
+	}
+	return s
+}
+
+func (s *surface) inside(x, y, margin int) bool {
+	return x >= margin && x < s.width-margin && y >= margin && y < s.height-margin
+}
+
+// update adds ev to the surface, returning false if ev is outside of the sensor or filtered
+func (s *surface) update(ev event.Event) bool {
+	if !s.inside(ev.Coords.X, ev.Coords.Y, 0) {
+		return false
+	}
+
+	p, i := ev.P&1, ev.Coords.Y*s.width+ev.Coords.X
+	previous := s.latest[p][i]
+	s.latest[p][i] = ev.Ts
+	if s.filterUs > 0 && ev.Ts-previous <= s.filterUs {
+		return false
+	}
+
+	s.sae[p][i] = ev.Ts
+	return true
+}
+
+// circle returns the timestamps of the circle around ev, in the SAE of its polarity
+func (s *surface) circle(ev event.Event, offsets []event.Point2D, dst []int) []int {
+	dst = dst[:0]
+	sae := s.sae[ev.P&1]
+	for _, o := range offsets {
+		dst = append(dst, sae[(ev.Coords.Y+o.Y)*s.width+ev.Coords.X+o.X])
+	}
+	return dst
+}
+
+// detect keeps the events of src for which corner returns true
+func detect(src []event.Event, corner func(ev event.Event) bool) []event.Event {
+	dst := []event.Event{}
+	for _, ev := range src {
+		if corner(ev) {
+			dst = append(dst, ev)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,130 @@
+package corners
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+const (
+	squareSize = 12
+	steps      = 20
+)
+
+// movingSquare creates the ON events of the leading edges of a square moving diagonally one pixel per millissecond
+func movingSquare() []event.Event {
+	ev := []event.Event{}
+	for k := 1; k < steps; k++ {
+		for y := 5 + k; y < 5+k+squareSize; y++ {
+			for x := 5 + k; x < 5+k+squareSize; x++ {
+				if x == 5+k+squareSize-1 || y == 5+k+squareSize-1 {
+					ev = append(ev, event.Event{Coords: event.Point2D{X: x, Y: y}, Ts: k * 1000, P: 1})
+				}
+			}
+		}
+	}
+	return ev
+}
+
+// nearCorner returns true if ev is close to a corner of the square at the time of ev
+func nearCorner(ev event.Event) bool {
+	k := ev.Ts / 1000
+	for _, c := range []event.Point2D{
+		{X: 5 + k + squareSize - 1, Y: 5 + k + squareSize - 1},
+		{X: 5 + k, Y: 5 + k + squareSize - 1},
+		{X: 5 + k + squareSize - 1, Y: 5 + k},
+	} {
+		dx, dy := ev.Coords.X-c.X, ev.Coords.Y-c.Y
+		if dx >= -2 && dx <= 2 && dy >= -2 && dy <= 2 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDetectors(t *testing.T) {
+	src := movingSquare()
+
+	tests := []struct {
+		name      string
+		d         Detector
+		precision float64
+	}{
+		{name: "Test eFAST", d: EFast{Width: 48, Height: 48}, precision: 1},
+		{name: "Test Arc*", d: ArcStar{Width: 48, Height: 48}, precision: 1},
+		{name: "Test eHarris", d: EHarris{Width: 48, Height: 48, Threshold: 0.02}, precision: 0.95},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.d.Detect(src)
+			near := 0
+			for _, ev := range got {
+				if nearCorner(ev) {
+					near++
+				}
+			}
+			if len(got) < steps || float64(near) < tt.precision*float64(len(got)) {
+				t.Errorf("Detect() = %d corners, %d near square corners", len(got), near)
+			}
+		})
+	}
+}
+
+func TestFastArc(t *testing.T) {
+	tests := []struct {
+		name string
+		ts   []int
+		want bool
+	}{
+		{name: "Test recent arc", ts: []int{9, 9, 9, 9, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, want: true},
+		{name: "Test wrapped arc", ts: []int{9, 9, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 9}, want: true},
+		{name: "Test short arc", ts: []int{9, 9, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, want: false},
+		{name: "Test long arc", ts: []int{9, 9, 9, 9, 9, 9, 9, 9, 1, 1, 1, 1, 1, 1, 1, 1}, want: false},
+		{name: "Test interrupted arc", ts: []int{9, 9, 0, 9, 9, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fastArc(tt.ts, 3, 6); got != tt.want {
+				t.Errorf("fastArc() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArcStar(t *testing.T) {
+	tests := []struct {
+		name string
+		ts   []int
+		want bool
+	}{
+		{name: "Test recent arc", ts: []int{9, 8, 7, 6, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, want: true},
+		{name: "Test complementary arc", ts: []int{9, 8, 7, 6, 5, 5, 5, 5, 5, 5, 5, 5, 1, 1, 1, 1}, want: true},
+		{name: "Test edge", ts: []int{9, 9, 9, 9, 9, 9, 9, 9, 1, 1, 1, 1, 1, 1, 1, 1}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := arcStar(tt.ts, 3, 6); got != tt.want {
+				t.Errorf("arcStar() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	src := []event.Event{
+		{Coords: event.Point2D{X: 1, Y: 1}, Ts: 0, P: 1},
+		{Coords: event.Point2D{X: 1, Y: 1}, Ts: 10, P: 1},
+		{Coords: event.Point2D{X: 1, Y: 1}, Ts: 20, P: 0},
+		{Coords: event.Point2D{X: 1, Y: 1}, Ts: 55, P: 1},
+	}
+
+	s := newSurface(4, 4, 50)
+	got := []bool{}
+	for _, ev := range src {
+		got = append(got, s.update(ev))
+	}
+	if want := []bool{true, false, true, false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("surface.update() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+package corners
+
+import (
+	"github.com/ffardo/go-event-vision"
+)
+
+// EFast implements the eFAST corner detector. An event is a corner when, in the SAE of its polarity, an arc of 3
+// to 6 pixels of the circle of radius 3 and an arc of 4 to 8 pixels of the circle of radius 4 around it are more
+// recent than the remaining pixels of their circles.
+type EFast struct {
+	Width    int
+	Height   int
+	FilterUs int // minimum time between events of a pixel updating the SAE, as in the original work. 0 disables the filter
+}
+
+// fastArc returns true if a contiguous arc of minLen to maxLen elements is more recent than every other element
+func fastArc(ts []int, minLen, maxLen int) bool {
+	n := len(ts)
+	for start := 0; start < n; start++ {
+		arcMin := ts[start]
+		for l := 1; l <= maxLen; l++ {
+			if v := ts[(start+l-1)%n]; v < arcMin {
+				arcMin = v
+			}
+			if l < minLen {
+				continue
+			}
+
+			newer := true
+			for k := l; k < n; k++ {
+				if ts[(start+k)%n] >= arcMin {
+					newer = false
+					break
+				}
+			}
+			if newer {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Detect keeps the events of src classified as corners
+func (e EFast) Detect(src []event.Event) []event.Event {
+	s := newSurface(e.Width, e.Height, e.FilterUs)
+	ts := []int{}
+
+	return detect(src, func(ev event.Event) bool {
+		if !s.update(ev) || !s.inside(ev.Coords.X, ev.Coords.Y, circleRadius) {
+			return false
+		}
+
+		ts = s.circle(ev, innerCircle, ts)
+		if !fastArc(ts, 3, 6) {
+			return false
+		}
+		ts = s.circle(ev, outerCircle, ts)
+		return fastArc(ts, 4, 8)
+	})
+}
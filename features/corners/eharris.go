@@ -0,0 +1,101 @@
+package corners
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+// EHarris implements the event-based Harris corner detector. The most recent pixels of a patch around each event,
+// in the SAE of its polarity, form a binary image whose Harris score classifies the event.
+type EHarris struct {
+	Width     int
+	Height    int
+	Radius    int     // patch radius, 4 (9 x 9 patch) when zero
+	Events    int     // number of most recent pixels set in the binary patch, 25 when zero
+	K         float64 // Harris sensitivity, 0.04 when zero
+	Threshold float64 // minimum Harris score of corners, from gradients normalized to [-1, 1]. Around 0.02 for moving edges
+	FilterUs  int     // minimum time between events of a pixel updating the SAE. 0 disables the filter
+}
+
+// harris computes the Harris score of the center of a binary patch with Sobel gradients and Gaussian weights.
+// Gradients and weights are normalized, so scores do not depend on the patch size.
+func harris(patch [][]float64, k float64) float64 {
+	size := len(patch)
+	center := float64(size-1) / 2
+	sigma := center / 2
+
+	var sxx, sxy, syy, sw float64
+	for y := 1; y < size-1; y++ {
+		for x := 1; x < size-1; x++ {
+			gx := patch[y-1][x+1] + 2*patch[y][x+1] + patch[y+1][x+1] - patch[y-1][x-1] - 2*patch[y][x-1] - patch[y+1][x-1]
+			gy := patch[y+1][x-1] + 2*patch[y+1][x] + patch[y+1][x+1] - patch[y-1][x-1] - 2*patch[y-1][x] - patch[y-1][x+1]
+			gx, gy = gx/4, gy/4
+
+			dx, dy := float64(x)-center, float64(y)-center
+			w := math.Exp(-(dx*dx + dy*dy) / (2 * sigma * sigma))
+			sxx += w * gx * gx
+			sxy += w * gx * gy
+			syy += w * gy * gy
+			sw += w
+		}
+	}
+	sxx, sxy, syy = sxx/sw, sxy/sw, syy/sw
+
+	trace := sxx + syy
+	return sxx*syy - sxy*sxy - k*trace*trace
+}
+
+// Detect keeps the events of src classified as corners
+func (h EHarris) Detect(src []event.Event) []event.Event {
+	radius, count, k := h.Radius, h.Events, h.K
+	if radius <= 0 {
+		radius = 4
+	}
+	if count <= 0 {
+		count = 25
+	}
+	if k == 0 {
+		k = 0.04
+	}
+
+	s := newSurface(h.Width, h.Height, h.FilterUs)
+	size := 2*radius + 1
+	patch := make([][]float64, size)
+	for i := range patch {
+		patch[i] = make([]float64, size)
+	}
+	recent := make([]int, 0, size*size)
+
+	return detect(src, func(ev event.Event) bool {
+		if !s.update(ev) || !s.inside(ev.Coords.X, ev.Coords.Y, radius) {
+			return false
+		}
+
+		sae := s.sae[ev.P&1]
+		recent = recent[:0]
+		for y := -radius; y <= radius; y++ {
+			for x := -radius; x <= radius; x++ {
+				recent = append(recent, sae[(ev.Coords.Y+y)*h.Width+ev.Coords.X+x])
+			}
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(recent)))
+		oldest := recent[len(recent)-1]
+		if count <= len(recent) {
+			oldest = recent[count-1]
+		}
+
+		for y := -radius; y <= radius; y++ {
+			for x := -radius; x <= radius; x++ {
+				ts := sae[(ev.Coords.Y+y)*h.Width+ev.Coords.X+x]
+				patch[y+radius][x+radius] = 0
+				if ts >= oldest && ts != unset {
+					patch[y+radius][x+radius] = 1
+				}
+			}
+		}
+
+		return harris(patch, k) > h.Threshold
+	})
+}
@@ -0,0 +1,235 @@
+package filter
+
+import (
+	"github.com/ffardo/go-event-vision"
+)
+
+// Denoiser specifies a common interface for event stream filters, so different filters can be applied
+// and compared on the same input. Apply must not modify the source slice.
+type Denoiser interface {
+	Apply(src []event.Event) []event.Event
+}
+
+// BackgroundActivity implements Denoiser using SpatioTemporalRadius
+type BackgroundActivity struct {
+	Width  int // sensor width
+	Height int // sensor height
+	Radius int // neighbourhood radius in pixels
+	UsTime int // correlation time window in microsseconds
+}
+
+// Apply filters events with SpatioTemporalRadius
+func (b BackgroundActivity) Apply(src []event.Event) []event.Event {
+	return SpatioTemporalRadius(src, b.Width, b.Height, b.Radius, b.UsTime)
+}
+
+// Refraction implements Denoiser using ApplyRefraction
+type Refraction struct {
+	UsTime int // refractory period in microsseconds
+}
+
+// Apply filters events with ApplyRefraction
+func (r Refraction) Apply(src []event.Event) []event.Event {
+	return ApplyRefraction(src, r.UsTime)
+}
+
+// HotPixel implements Denoiser using HotPixels
+type HotPixel struct {
+	Width  int     // sensor width
+	Height int     // sensor height
+	K      float64 // number of robust standard deviations above the median count
+}
+
+// Apply filters events with HotPixels
+func (h HotPixel) Apply(src []event.Event) []event.Event {
+	dst, _ := HotPixels(src, h.Width, h.Height, h.K)
+	return dst
+}
+
+// timeSurface keeps the last timestamp and polarity of every pixel of the sensor in flat arrays
+type timeSurface struct {
+	width  int
+	height int
+	ts     []int
+	p      []int
+	active []bool
+}
+
+func newTimeSurface(width, height int) timeSurface {
+	if width < 0 || height < 0 {
+		width, height = 0, 0
+	}
+	return timeSurface{
+		width:  width,
+		height: height,
+		ts:     make([]int, width*height),
+		p:      make([]int, width*height),
+		active: make([]bool, width*height),
+	}
+}
+
+func (s timeSurface) inside(x, y int) bool {
+	return x >= 0 && x < s.width && y >= 0 && y < s.height
+}
+
+func (s timeSurface) update(ev event.Event) {
+	idx := ev.Coords.Y*s.width + ev.Coords.X
+	s.ts[idx] = ev.Ts
+	s.p[idx] = ev.P
+	s.active[idx] = true
+}
+
+// count returns how many pixels within radius of ev fired within usTime. The pixel of the event itself is
+// only counted if self is set and other polarities are only counted if samePolarity is not set.
+func (s timeSurface) count(ev event.Event, radius, usTime int, self, samePolarity bool) int {
+	c := 0
+	for y := intMax(0, ev.Coords.Y-radius); y <= intMin(s.height-1, ev.Coords.Y+radius); y++ {
+		for x := intMax(0, ev.Coords.X-radius); x <= intMin(s.width-1, ev.Coords.X+radius); x++ {
+			if !self && x == ev.Coords.X && y == ev.Coords.Y {
+				continue
+			}
+			idx := y*s.width + x
+			if s.active[idx] && ev.Ts-s.ts[idx] <= usTime && (!samePolarity || s.p[idx] == ev.P) {
+				c++
+			}
+		}
+	}
+	return c
+}
+
+/*
+STCF implements the Spatio-Temporal Correlation Filter.
+An event is allowed through the filter only if at least 'K' other pixels within 'Radius'
+pixels fired within 'UsTime' microseconds, regardless of polarity. With K set to 1 it
+behaves as a background activity filter without the exception for repeated events.
+Events outside the sensor are removed.
+*/
+type STCF struct {
+	Width  int // sensor width
+	Height int // sensor height
+	Radius int // neighbourhood radius in pixels
+	UsTime int // correlation time window in microsseconds
+	K      int // minimum number of correlated neighbours
+}
+
+// Apply filters events with the Spatio-Temporal Correlation Filter
+func (f STCF) Apply(src []event.Event) []event.Event {
+	s := newTimeSurface(f.Width, f.Height)
+	dst := []event.Event{}
+
+	for _, ev := range src {
+		if !s.inside(ev.Coords.X, ev.Coords.Y) {
+			continue
+		}
+		if s.count(ev, f.Radius, f.UsTime, false, false) >= f.K {
+			dst = append(dst, ev)
+		}
+		s.update(ev)
+	}
+
+	return dst
+}
+
+type kNoiseCell struct {
+	pos    int // position along the other axis
+	ts     int
+	active bool
+}
+
+/*
+KNoise implements the O(N) background activity filter by Khodamoradi and Kastner.
+Instead of a per pixel memory, only the last event of each column and of each row is
+kept, using width + height cells. An event is allowed through the filter if the last
+event of an adjacent column or row lies in a neighbouring pixel and happened within
+'UsTime' microseconds.
+Events outside the sensor are removed.
+*/
+type KNoise struct {
+	Width  int // sensor width
+	Height int // sensor height
+	UsTime int // correlation time window in microsseconds
+}
+
+func (f KNoise) supported(cells []kNoiseCell, idx, pos int, ev event.Event) bool {
+	for i := intMax(0, idx-1); i <= intMin(len(cells)-1, idx+1); i++ {
+		c := cells[i]
+		if !c.active || ev.Ts-c.ts > f.UsTime || c.pos < pos-1 || c.pos > pos+1 {
+			continue
+		}
+		if i == idx && c.pos == pos {
+			// the cell refers to the pixel of the event itself
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// Apply filters events with the K-Noise filter
+func (f KNoise) Apply(src []event.Event) []event.Event {
+	if f.Width <= 0 || f.Height <= 0 {
+		return []event.Event{}
+	}
+
+	columns := make([]kNoiseCell, f.Width)
+	rows := make([]kNoiseCell, f.Height)
+	dst := []event.Event{}
+
+	for _, ev := range src {
+		x, y := ev.Coords.X, ev.Coords.Y
+		if x < 0 || x >= f.Width || y < 0 || y >= f.Height {
+			continue
+		}
+
+		if f.supported(columns, x, y, ev) || f.supported(rows, y, x, ev) {
+			dst = append(dst, ev)
+		}
+
+		columns[x] = kNoiseCell{pos: y, ts: ev.Ts, active: true}
+		rows[y] = kNoiseCell{pos: x, ts: ev.Ts, active: true}
+	}
+
+	return dst
+}
+
+/*
+YNoise implements a density based filter in the spirit of Y-Noise by Feng et al.
+Each pixel only memorizes its last timestamp and polarity. The event density is the
+number of pixels, including the pixel of the event, within 'Radius' pixels which fired
+an event of the same polarity within 'UsTime' microseconds. Events are allowed through
+the filter when the density reaches 'Threshold'.
+When 'RefractoryPeriod' is set, events closer than this to the previous event of the
+same pixel are removed, which suppresses hot pixels.
+Events outside the sensor are removed.
+*/
+type YNoise struct {
+	Width            int // sensor width
+	Height           int // sensor height
+	Radius           int // neighbourhood radius in pixels
+	UsTime           int // correlation time window in microsseconds
+	Threshold        int // minimum event density
+	RefractoryPeriod int // refractory period in microsseconds, 0 disables it
+}
+
+// Apply filters events with the Y-Noise filter
+func (f YNoise) Apply(src []event.Event) []event.Event {
+	s := newTimeSurface(f.Width, f.Height)
+	dst := []event.Event{}
+
+	for _, ev := range src {
+		if !s.inside(ev.Coords.X, ev.Coords.Y) {
+			continue
+		}
+
+		idx := ev.Coords.Y*s.width + ev.Coords.X
+		refractory := f.RefractoryPeriod > 0 && s.active[idx] && ev.Ts-s.ts[idx] < f.RefractoryPeriod
+
+		s.update(ev)
+
+		if !refractory && s.count(ev, f.Radius, f.UsTime, true, true) >= f.Threshold {
+			dst = append(dst, ev)
+		}
+	}
+
+	return dst
+}
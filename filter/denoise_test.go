@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+func TestDenoisers(t *testing.T) {
+	// A short edge moving along the first row, an isolated noise event and a pixel firing repeatedly
+	src := []event.Event{
+		{Coords: event.Point2D{X: 0, Y: 0}, Ts: 100, P: 1},
+		{Coords: event.Point2D{X: 1, Y: 0}, Ts: 200, P: 1},
+		{Coords: event.Point2D{X: 2, Y: 0}, Ts: 300, P: 1},
+		{Coords: event.Point2D{X: 6, Y: 6}, Ts: 350, P: 0},
+		{Coords: event.Point2D{X: 4, Y: 4}, Ts: 400, P: 0},
+		{Coords: event.Point2D{X: 4, Y: 4}, Ts: 410, P: 0},
+		{Coords: event.Point2D{X: 4, Y: 4}, Ts: 420, P: 0},
+	}
+
+	tests := []struct {
+		name     string
+		denoiser Denoiser
+		want     []event.Event
+	}{
+		{
+			name:     "Test STCF with one neighbour",
+			denoiser: STCF{Width: 8, Height: 8, Radius: 1, UsTime: 150, K: 1},
+			want:     src[1:3],
+		},
+		{
+			name:     "Test STCF with two neighbours",
+			denoiser: STCF{Width: 8, Height: 8, Radius: 2, UsTime: 250, K: 2},
+			want:     src[2:3],
+		},
+		{
+			name:     "Test KNoise",
+			denoiser: KNoise{Width: 8, Height: 8, UsTime: 150},
+			want:     src[1:3],
+		},
+		{
+			name:     "Test YNoise does not let a pixel support itself",
+			denoiser: YNoise{Width: 8, Height: 8, Radius: 1, UsTime: 150, Threshold: 2},
+			want:     src[1:3],
+		},
+		{
+			name:     "Test BackgroundActivity",
+			denoiser: BackgroundActivity{Width: 8, Height: 8, Radius: 1, UsTime: 150},
+			want:     []event.Event{src[1], src[2], src[5], src[6]},
+		},
+		{
+			name:     "Test Refraction",
+			denoiser: Refraction{UsTime: 50},
+			want:     src[:5],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.denoiser.Apply(src); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYNoiseRefractoryPeriod(t *testing.T) {
+	src := []event.Event{
+		{Coords: event.Point2D{X: 0, Y: 0}, Ts: 100, P: 1},
+		{Coords: event.Point2D{X: 1, Y: 0}, Ts: 200, P: 1},
+		{Coords: event.Point2D{X: 1, Y: 0}, Ts: 220, P: 1},
+	}
+
+	tests := []struct {
+		name       string
+		refractory int
+		want       []event.Event
+	}{
+		{name: "Test without refractory period", refractory: 0, want: src[1:]},
+		{name: "Test with refractory period", refractory: 50, want: src[1:2]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := YNoise{Width: 4, Height: 4, Radius: 1, UsTime: 150, Threshold: 2, RefractoryPeriod: tt.refractory}
+			if got := f.Apply(src); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("YNoise.Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"math"
 
 	"github.com/ffardo/go-event-vision"
-	"github.com/ffardo/go-event-vision/sae"
 )
 
 func intMax(a, b int) int {
@@ -25,13 +24,38 @@ func intMin(a, b int) int {
 SpatioTemporal generate a filtered set of events.
 Uses a background activity filter on the events, such that only events which are
 correlated with a neighbouring event within 'usTime' microseconds will be allowed
-through the filter.
+through the filter. 'xMax' and 'yMax' are the sensor width and height and the
+neighbourhood is the 3x3 area around each event.
 */
 func SpatioTemporal(src []event.Event, xMax, yMax, usTime int) []event.Event {
-	t0 := make(map[event.Point2D]int)
+	return SpatioTemporalRadius(src, xMax, yMax, 1, usTime)
+}
+
+/*
+SpatioTemporalRadius is a background activity filter with a configurable neighbourhood.
+An event is allowed through the filter only if another pixel within 'radius' pixels
+fired within 'usTime' microseconds. Consecutive events at the same pixel with the same
+polarity are always kept. The last timestamp of each pixel is kept in a flat
+width x height array and events outside the sensor are removed.
+*/
+func SpatioTemporalRadius(src []event.Event, width, height, radius, usTime int) []event.Event {
+	if width <= 0 || height <= 0 {
+		return []event.Event{}
+	}
+
+	t0 := make([]int, width*height)
+	active := make([]bool, width*height)
+
+	inside := func(ev event.Event) bool {
+		return ev.Coords.X >= 0 && ev.Coords.X < width && ev.Coords.Y >= 0 && ev.Coords.Y < height
+	}
 
 	for _, ev := range src {
-		t0[ev.Coords] = -usTime
+		if inside(ev) {
+			idx := ev.Coords.Y*width + ev.Coords.X
+			t0[idx] = -usTime
+			active[idx] = true
+		}
 	}
 
 	xP := 0
@@ -43,18 +67,29 @@ func SpatioTemporal(src []event.Event, xMax, yMax, usTime int) []event.Event {
 	totalEvents := len(src)
 
 	for idx, dt := range src {
-		if xP != dt.Coords.X || yP != dt.Coords.Y || pP != dt.P {
-			t0[dt.Coords] = -usTime
-			minXSub := intMax(0, dt.Coords.X-1)
-			maxXSub := intMin(xMax, dt.Coords.X+1)
-			minYSub := intMax(0, dt.Coords.Y-1)
-			maxYSub := intMin(yMax, dt.Coords.Y+1)
+		if !inside(dt) {
+			ex[idx] = true
+			totalEvents--
+			continue
+		}
 
-			t0Temp := sae.CropMap(t0, minXSub, minYSub, (maxXSub-minXSub)+1, (maxYSub-minYSub)+1)
+		pos := dt.Coords.Y*width + dt.Coords.X
+
+		if xP != dt.Coords.X || yP != dt.Coords.Y || pP != dt.P {
+			t0[pos] = -usTime
+			minXSub := intMax(0, dt.Coords.X-radius)
+			maxXSub := intMin(width-1, dt.Coords.X+radius)
+			minYSub := intMax(0, dt.Coords.Y-radius)
+			maxYSub := intMin(height-1, dt.Coords.Y+radius)
 
 			minTs := int(math.MaxInt64)
-			for _, v := range t0Temp {
-				minTs = intMin(minTs, dt.Ts-v)
+			for y := minYSub; y <= maxYSub; y++ {
+				row := y * width
+				for x := minXSub; x <= maxXSub; x++ {
+					if active[row+x] {
+						minTs = intMin(minTs, dt.Ts-t0[row+x])
+					}
+				}
 			}
 
 			if minTs > usTime {
@@ -62,7 +97,7 @@ func SpatioTemporal(src []event.Event, xMax, yMax, usTime int) []event.Event {
 				totalEvents--
 			}
 		}
-		t0[dt.Coords] = dt.Ts
+		t0[pos] = dt.Ts
 		xP = dt.Coords.X
 		yP = dt.Coords.Y
 		pP = dt.P
@@ -1,12 +1,71 @@
 package filter
 
 import (
+	"math"
+	"math/rand"
 	"reflect"
 	"testing"
 
 	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/sae"
 )
 
+// spatioTemporalMap is the original map based background activity filter, kept as a reference
+// for equivalence tests and benchmarks
+func spatioTemporalMap(src []event.Event, xMax, yMax, usTime int) []event.Event {
+	t0 := make(map[event.Point2D]int)
+
+	for _, ev := range src {
+		t0[ev.Coords] = -usTime
+	}
+
+	xP := 0
+	yP := 0
+	pP := 0
+
+	dst := []event.Event{}
+
+	for _, dt := range src {
+		keep := true
+		if xP != dt.Coords.X || yP != dt.Coords.Y || pP != dt.P {
+			t0[dt.Coords] = -usTime
+			minXSub := intMax(0, dt.Coords.X-1)
+			maxXSub := intMin(xMax, dt.Coords.X+1)
+			minYSub := intMax(0, dt.Coords.Y-1)
+			maxYSub := intMin(yMax, dt.Coords.Y+1)
+
+			t0Temp := sae.CropMap(t0, minXSub, minYSub, (maxXSub-minXSub)+1, (maxYSub-minYSub)+1)
+
+			minTs := int(math.MaxInt64)
+			for _, v := range t0Temp {
+				minTs = intMin(minTs, dt.Ts-v)
+			}
+
+			keep = minTs <= usTime
+		}
+		if keep {
+			dst = append(dst, dt)
+		}
+		t0[dt.Coords] = dt.Ts
+		xP = dt.Coords.X
+		yP = dt.Coords.Y
+		pP = dt.P
+	}
+
+	return dst
+}
+
+func randomEvents(n, width, height, usStep int) []event.Event {
+	r := rand.New(rand.NewSource(1))
+	ev := make([]event.Event, n)
+	ts := 0
+	for i := range ev {
+		ts += r.Intn(usStep)
+		ev[i] = event.Event{Coords: event.Point2D{X: r.Intn(width), Y: r.Intn(height)}, Ts: ts, P: r.Intn(2)}
+	}
+	return ev
+}
+
 func TestSpatioTemporal(t *testing.T) {
 	type args struct {
 		src    []event.Event
@@ -50,6 +109,72 @@ func TestSpatioTemporal(t *testing.T) {
 	}
 }
 
+func TestSpatioTemporalRadius(t *testing.T) {
+	src := []event.Event{
+		{Coords: event.Point2D{X: 0, Y: 0}, Ts: 100, P: 1},
+		{Coords: event.Point2D{X: 2, Y: 0}, Ts: 200, P: 1},
+		{Coords: event.Point2D{X: 3, Y: 3}, Ts: 300, P: 1},
+		{Coords: event.Point2D{X: 4, Y: 3}, Ts: 400, P: 0},
+		{Coords: event.Point2D{X: 5, Y: 3}, Ts: 500, P: 0},
+	}
+
+	tests := []struct {
+		name   string
+		radius int
+		want   []event.Event
+	}{
+		{
+			name:   "Test radius 1 only correlates adjacent pixels",
+			radius: 1,
+			want: []event.Event{
+				{Coords: event.Point2D{X: 4, Y: 3}, Ts: 400, P: 0},
+			},
+		},
+		{
+			name:   "Test radius 2 correlates pixels two pixels apart",
+			radius: 2,
+			want: []event.Event{
+				{Coords: event.Point2D{X: 2, Y: 0}, Ts: 200, P: 1},
+				{Coords: event.Point2D{X: 4, Y: 3}, Ts: 400, P: 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SpatioTemporalRadius(src, 5, 5, tt.radius, 150); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SpatioTemporalRadius() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpatioTemporalMatchesMapImplementation(t *testing.T) {
+	src := randomEvents(20000, 64, 48, 20)
+
+	got := SpatioTemporal(src, 64, 48, 1000)
+	want := spatioTemporalMap(src, 64, 48, 1000)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SpatioTemporal() kept %d events, map implementation kept %d", len(got), len(want))
+	}
+}
+
+func BenchmarkSpatioTemporal(b *testing.B) {
+	src := randomEvents(100000, 304, 240, 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		SpatioTemporal(src, 304, 240, 5000)
+	}
+}
+
+func BenchmarkSpatioTemporalMap(b *testing.B) {
+	src := randomEvents(100000, 304, 240, 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		spatioTemporalMap(src, 304, 240, 5000)
+	}
+}
+
 func TestApplyRefraction(t *testing.T) {
 	type args struct {
 		src    []event.Event
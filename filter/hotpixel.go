@@ -0,0 +1,103 @@
+package filter
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+/*
+DetectHotPixels flags pixels which fire far more often than the rest of the sensor.
+The event count of every active pixel of a width x height sensor is computed and a pixel is
+flagged as hot when its count exceeds the median count by more than 'k' robust standard
+deviations. The deviation is estimated from the median absolute deviation, and never assumed
+smaller than the Poisson deviation of the median count, so hot pixels do not inflate it.
+To calibrate on a time window instead of the whole capture, pass the output of ByTime.
+The resulting mask is indexed as mask[y][x], like sae matrices.
+*/
+func DetectHotPixels(src []event.Event, width, height int, k float64) [][]bool {
+	mask := make([][]bool, height)
+	counts := make([][]int, height)
+	for i := 0; i < height; i++ {
+		mask[i] = make([]bool, width)
+		counts[i] = make([]int, width)
+	}
+
+	for _, ev := range src {
+		if ev.Coords.X >= 0 && ev.Coords.X < width && ev.Coords.Y >= 0 && ev.Coords.Y < height {
+			counts[ev.Coords.Y][ev.Coords.X]++
+		}
+	}
+
+	active := []float64{}
+	for _, row := range counts {
+		for _, c := range row {
+			if c > 0 {
+				active = append(active, float64(c))
+			}
+		}
+	}
+
+	if len(active) == 0 {
+		return mask
+	}
+
+	med := median(active)
+
+	deviations := make([]float64, len(active))
+	for i, c := range active {
+		deviations[i] = math.Abs(c - med)
+	}
+
+	// 1.4826 scales the median absolute deviation to the standard deviation of a normal distribution
+	sigma := math.Max(1.4826*median(deviations), math.Max(math.Sqrt(med), 1.0))
+	limit := med + k*sigma
+
+	for y, row := range counts {
+		for x, c := range row {
+			mask[y][x] = float64(c) > limit
+		}
+	}
+
+	return mask
+}
+
+/*
+RemoveHotPixels removes every event located at a pixel flagged in mask.
+Events outside the mask area are kept.
+*/
+func RemoveHotPixels(src []event.Event, mask [][]bool) []event.Event {
+	dst := make([]event.Event, 0, len(src))
+
+	for _, ev := range src {
+		x, y := ev.Coords.X, ev.Coords.Y
+		if y >= 0 && y < len(mask) && x >= 0 && x < len(mask[y]) && mask[y][x] {
+			continue
+		}
+		dst = append(dst, ev)
+	}
+
+	return dst
+}
+
+/*
+HotPixels detects hot pixels over the whole event stream and removes their events.
+It returns the filtered events along with the hot pixel mask for inspection.
+*/
+func HotPixels(src []event.Event, width, height int, k float64) ([]event.Event, [][]bool) {
+	mask := DetectHotPixels(src, width, height, k)
+
+	return RemoveHotPixels(src, mask), mask
+}
+
+func median(values []float64) float64 {
+	s := append([]float64{}, values...)
+	sort.Float64s(s)
+
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2.0
+}
@@ -0,0 +1,79 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+func TestHotPixels(t *testing.T) {
+	src := []event.Event{}
+
+	// Every pixel of a 4x4 sensor fires a couple of times while pixel (2,1) fires continuously
+	for ts := 0; ts < 100; ts++ {
+		src = append(src, event.Event{Coords: event.Point2D{X: 2, Y: 1}, Ts: ts * 10, P: 1})
+		if ts%50 == 0 {
+			for y := 0; y < 4; y++ {
+				for x := 0; x < 4; x++ {
+					src = append(src, event.Event{Coords: event.Point2D{X: x, Y: y}, Ts: ts * 10, P: 0})
+				}
+			}
+		}
+	}
+
+	got, mask := HotPixels(src, 4, 4, 5.0)
+
+	wantMask := [][]bool{
+		{false, false, false, false},
+		{false, false, true, false},
+		{false, false, false, false},
+		{false, false, false, false},
+	}
+
+	if !reflect.DeepEqual(mask, wantMask) {
+		t.Errorf("HotPixels() mask = %v, want %v", mask, wantMask)
+	}
+
+	if len(got) != 30 {
+		t.Errorf("HotPixels() kept %d events, want 30", len(got))
+	}
+
+	for _, ev := range got {
+		if ev.Coords.X == 2 && ev.Coords.Y == 1 {
+			t.Fatalf("HotPixels() should remove all events of hot pixels, got %v", ev)
+		}
+	}
+}
+
+func TestDetectHotPixels(t *testing.T) {
+	tests := []struct {
+		name string
+		src  []event.Event
+		want [][]bool
+	}{
+		{
+			name: "Test empty stream has no hot pixels",
+			src:  []event.Event{},
+			want: [][]bool{{false, false}, {false, false}},
+		},
+		{
+			name: "Test uniform activity has no hot pixels",
+			src: []event.Event{
+				{Coords: event.Point2D{X: 0, Y: 0}, Ts: 1, P: 1},
+				{Coords: event.Point2D{X: 1, Y: 0}, Ts: 2, P: 1},
+				{Coords: event.Point2D{X: 0, Y: 1}, Ts: 3, P: 1},
+				{Coords: event.Point2D{X: 1, Y: 1}, Ts: 4, P: 1},
+				{Coords: event.Point2D{X: 5, Y: 5}, Ts: 5, P: 1},
+			},
+			want: [][]bool{{false, false}, {false, false}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectHotPixels(tt.src, 2, 2, 3.0); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectHotPixels() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+/*
+flow implements event-based optical flow with local plane fitting, as proposed by Benosman et al. in
+"Event-Based Visual Flow" (2014). The surface of active events of each polarity is updated with every incoming
+event and a plane is fitted to the recent timestamps around it. The gradient of the plane gives the normal flow.
+*/
+package flow
+
+import (
+	"math"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+// Vector is the normal flow estimated for an event
+type Vector struct {
+	Event    event.Event
+	VX, VY   float64 // flow in pixels per second
+	Residual float64 // root mean square residual of the plane fit, in microsseconds. Lower is a better fit
+	Support  int     // number of timestamps used in the fit
+}
+
+// Speed returns the magnitude of the flow, in pixels per second
+func (v Vector) Speed() float64 {
+	return math.Hypot(v.VX, v.VY)
+}
+
+// PlaneFitting estimates optical flow by fitting planes to the surface of active events
+type PlaneFitting struct {
+	Width        int
+	Height       int
+	Radius       int     // neighborhood radius, in pixels
+	UsTime       int     // timestamps older than UsTime are not fitted
+	MinSupport   int     // minimum number of timestamps of a fit, 5 when lower than 3
+	MaxResidual  float64 // the timestamp farthest from the plane is rejected and the plane refitted while farther than MaxResidual microsseconds. 0 disables rejection
+	MaxIteration int     // maximum number of rejected timestamps, 3 when zero
+}
+
+const unset = math.MinInt32
+
+type point struct {
+	dx, dy float64
+	dt     float64 // microsseconds
+}
+
+// fit solves the least squares plane dt = a*dx + b*dy + c
+func fit(points []point) (a, b, c float64, ok bool) {
+	var sxx, sxy, syy, sx, sy, sxt, syt, st float64
+	n := float64(len(points))
+	for _, p := range points {
+		sxx += p.dx * p.dx
+		sxy += p.dx * p.dy
+		syy += p.dy * p.dy
+		sx += p.dx
+		sy += p.dy
+		sxt += p.dx * p.dt
+		syt += p.dy * p.dt
+		st += p.dt
+	}
+
+	// Cramer's rule on the normal equations
+	m := [3][3]float64{{sxx, sxy, sx}, {sxy, syy, sy}, {sx, sy, n}}
+	r := [3]float64{sxt, syt, st}
+	det := det3(m)
+	if math.Abs(det) < 1e-9 {
+		return 0, 0, 0, false
+	}
+
+	solution := [3]float64{}
+	for col := range solution {
+		mc := m
+		for row := range mc {
+			mc[row][col] = r[row]
+		}
+		solution[col] = det3(mc) / det
+	}
+	return solution[0], solution[1], solution[2], true
+}
+
+func det3(m [3][3]float64) float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// Apply estimates the flow of src, which is expected to be sorted by timestamp.
+// Only events with a valid plane fit, with enough support and a non flat plane, produce a Vector.
+func (p PlaneFitting) Apply(src []event.Event) []Vector {
+	minSupport := p.MinSupport
+	if minSupport < 3 {
+		minSupport = 5
+	}
+	maxIteration := p.MaxIteration
+	if maxIteration <= 0 {
+		maxIteration = 3
+	}
+
+	// one surface per polarity, as sae.METHOD_RECENT computes for the whole stream
+	surfaces := [2][]int{make([]int, p.Width*p.Height), make([]int, p.Width*p.Height)}
+	for _, s := range surfaces {
+		for i := range s {
+			s[i] = unset
+		}
+	}
+
+	dst := []Vector{}
+	points := []point{}
+
+	for _, ev := range src {
+		if ev.Coords.X < 0 || ev.Coords.X >= p.Width || ev.Coords.Y < 0 || ev.Coords.Y >= p.Height {
+			continue
+		}
+
+		surface := surfaces[ev.P&1]
+		surface[ev.Coords.Y*p.Width+ev.Coords.X] = ev.Ts
+
+		points = points[:0]
+		for y := ev.Coords.Y - p.Radius; y <= ev.Coords.Y+p.Radius; y++ {
+			for x := ev.Coords.X - p.Radius; x <= ev.Coords.X+p.Radius; x++ {
+				if x < 0 || x >= p.Width || y < 0 || y >= p.Height {
+					continue
+				}
+				ts := surface[y*p.Width+x]
+				if ts == unset || ev.Ts-ts > p.UsTime {
+					continue
+				}
+				points = append(points, point{dx: float64(x - ev.Coords.X), dy: float64(y - ev.Coords.Y), dt: float64(ts - ev.Ts)})
+			}
+		}
+
+		v, ok := p.estimate(ev, points, minSupport, maxIteration)
+		if ok {
+			dst = append(dst, v)
+		}
+	}
+
+	return dst
+}
+
+// estimate fits a plane to points, rejecting outliers, and converts its gradient to normal flow
+func (p PlaneFitting) estimate(ev event.Event, points []point, minSupport, maxIteration int) (Vector, bool) {
+	var a, b, c float64
+	for i := 0; ; i++ {
+		if len(points) < minSupport {
+			return Vector{}, false
+		}
+
+		var ok bool
+		a, b, c, ok = fit(points)
+		if !ok {
+			return Vector{}, false
+		}
+		if p.MaxResidual <= 0 || i == maxIteration {
+			break
+		}
+
+		worst, worstResidual := 0, 0.0
+		for j, pt := range points {
+			if r := math.Abs(a*pt.dx + b*pt.dy + c - pt.dt); r > worstResidual {
+				worst, worstResidual = j, r
+			}
+		}
+		if worstResidual <= p.MaxResidual {
+			break
+		}
+		points[worst] = points[len(points)-1]
+		points = points[:len(points)-1]
+	}
+
+	// the gradient is in microsseconds per pixel, and the normal flow is gradient / |gradient|^2
+	norm := a*a + b*b
+	if norm < 1e-12 {
+		return Vector{}, false
+	}
+
+	sumSq := 0.0
+	for _, pt := range points {
+		r := a*pt.dx + b*pt.dy + c - pt.dt
+		sumSq += r * r
+	}
+
+	return Vector{
+		Event:    ev,
+		VX:       a / norm * 1e6,
+		VY:       b / norm * 1e6,
+		Residual: math.Sqrt(sumSq / float64(len(points))),
+		Support:  len(points),
+	}, true
+}
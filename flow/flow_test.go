@@ -0,0 +1,83 @@
+package flow
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+// edge creates the events of a straight edge moving at vx, vy pixels per second, where pixel (x, y) fires
+// when the edge reaches it
+func edge(width, height int, vx, vy float64) []event.Event {
+	ev := []event.Event{}
+	speed2 := vx*vx + vy*vy
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			ts := (float64(x)*vx + float64(y)*vy) / speed2 * 1e6
+			ev = append(ev, event.Event{Coords: event.Point2D{X: x, Y: y}, Ts: int(math.Round(ts)) + 1e6, P: 1})
+		}
+	}
+
+	// sort by timestamp, keeping row-major order on ties
+	for i := 1; i < len(ev); i++ {
+		for j := i; j > 0 && ev[j].Ts < ev[j-1].Ts; j-- {
+			ev[j], ev[j-1] = ev[j-1], ev[j]
+		}
+	}
+	return ev
+}
+
+func TestPlaneFitting_Apply(t *testing.T) {
+	tests := []struct {
+		name   string
+		vx, vy float64
+	}{
+		{name: "Test horizontal motion", vx: 100, vy: 0},
+		{name: "Test vertical motion", vx: 0, vy: -50},
+		{name: "Test diagonal motion", vx: 60, vy: 80},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := PlaneFitting{Width: 20, Height: 20, Radius: 2, UsTime: 100e3, MaxResidual: 2000}
+			vectors := p.Apply(edge(20, 20, tt.vx, tt.vy))
+			if len(vectors) == 0 {
+				t.Fatalf("PlaneFitting.Apply() returned no flow")
+			}
+
+			for _, v := range vectors {
+				if math.Abs(v.VX-tt.vx) > 5 || math.Abs(v.VY-tt.vy) > 5 {
+					t.Errorf("PlaneFitting.Apply() = %v, %v at %v, want %v, %v", v.VX, v.VY, v.Event, tt.vx, tt.vy)
+					break
+				}
+				if v.Support < 5 || v.Residual > 1000 {
+					t.Errorf("PlaneFitting.Apply() support = %v, residual = %v", v.Support, v.Residual)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestPlaneFitting_Outliers(t *testing.T) {
+	p := PlaneFitting{Width: 5, Height: 5, Radius: 2, UsTime: 1e6, MaxResidual: 1000}
+
+	points := []point{}
+	for y := -2; y <= 2; y++ {
+		for x := -2; x <= 2; x++ {
+			points = append(points, point{dx: float64(x), dy: float64(y), dt: float64(x) * 10e3})
+		}
+	}
+	// a noise event on the surface
+	points[3].dt = 500e3
+
+	v, ok := p.estimate(event.Event{}, points, 5, 3)
+	if !ok || math.Abs(v.VX-100) > 1e-6 || math.Abs(v.VY) > 1e-6 || v.Support != 24 {
+		t.Errorf("estimate() = %v, %v, want 100 px/s with 24 inliers", v, ok)
+	}
+
+	flat := []point{{dx: 0, dy: 0}, {dx: 1, dy: 0}, {dx: 0, dy: 1}, {dx: 1, dy: 1}, {dx: -1, dy: 0}}
+	if _, ok := p.estimate(event.Event{}, flat, 5, 3); ok {
+		t.Errorf("estimate() on a flat surface should not produce flow")
+	}
+}
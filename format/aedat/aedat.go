@@ -0,0 +1,312 @@
+package aedat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/format"
+)
+
+const (
+	packetHeaderSize  = 28
+	polarityEventType = 1
+	polarityEventSize = 8
+	maxPacketEvents   = 4096
+	timestampBits     = 31
+)
+
+// Aedat3 implements AEDAT 3.1 format reading and writing. Only polarity events are supported,
+// packets of other event types, such as frames or IMU samples, are skipped when reading.
+// More information can be found in the official documentation
+// https://inivation.gitlab.io/dv/dv-docs/docs/aedat-formats/aedat31.html
+type Aedat3 struct {
+	FilePath string
+	Mode     format.Mode
+}
+
+type packetHeader struct {
+	EventType       int16
+	EventSource     int16
+	EventSize       int32
+	EventTSOffset   int32
+	EventTSOverflow int32
+	EventCapacity   int32
+	EventNumber     int32
+	EventValid      int32
+}
+
+func (a Aedat3) newEventFromBytes(data []byte, overflow int32) (event.Event, bool) {
+	address := binary.LittleEndian.Uint32(data[:4])
+	ts := int(binary.LittleEndian.Uint32(data[4:]))
+
+	valid := address&1 == 1
+	p := int((address >> 1) & 1)
+	y := int((address >> 2) & 0x7FFF)
+	x := int((address >> 17) & 0x7FFF)
+
+	return event.Event{
+		Coords: event.Point2D{X: x, Y: y},
+		P:      p,
+		Ts:     int(overflow)<<timestampBits | ts,
+	}, valid
+}
+
+func (a Aedat3) eventToBytes(ev event.Event) []byte {
+	data := make([]byte, polarityEventSize)
+
+	address := uint32(1) | uint32(ev.P&1)<<1 | uint32(ev.Coords.Y&0x7FFF)<<2 | uint32(ev.Coords.X&0x7FFF)<<17
+	binary.LittleEndian.PutUint32(data[:4], address)
+	binary.LittleEndian.PutUint32(data[4:], uint32(ev.Ts&(1<<timestampBits-1)))
+
+	return data
+}
+
+// skipHeader consumes the ASCII header, whose lines all start with '#', and returns its size
+func (a Aedat3) skipHeader(r *bufio.Reader) (int64, error) {
+	offset := int64(0)
+	for {
+		b, err := r.Peek(1)
+		if err == io.EOF {
+			return offset, nil
+		}
+		if err != nil {
+			return offset, err
+		}
+		if b[0] != '#' {
+			return offset, nil
+		}
+
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return offset, format.NewError(format.ErrBadHeader, offset, "incomplete AEDAT header")
+		}
+		if err != nil {
+			return offset, err
+		}
+		offset += int64(len(line))
+		if bytes.HasPrefix(line, []byte("#!END-HEADER")) {
+			return offset, nil
+		}
+	}
+}
+
+// truncated reports truncated data at offset in Strict mode, and returns nil in Lenient mode
+func truncated(mode format.Mode, offset int64, detail string) error {
+	if mode == format.Lenient {
+		return nil
+	}
+	return format.NewError(format.ErrTruncated, offset, detail)
+}
+
+// countingWriter counts the bytes written, to report the offset of errors
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// keep returns the events of src for which check returns no error description
+func keep(src []event.Event, check func(ev event.Event) string) []event.Event {
+	dst := make([]event.Event, 0, len(src))
+	for _, ev := range src {
+		if check(ev) == "" {
+			dst = append(dst, ev)
+		}
+	}
+	return dst
+}
+
+// checkEvent reports why ev does not fit the polarity event layout
+func (a Aedat3) checkEvent(ev event.Event) string {
+	switch {
+	case ev.Coords.X < 0 || ev.Coords.X > 0x7FFF || ev.Coords.Y < 0 || ev.Coords.Y > 0x7FFF:
+		return fmt.Sprintf("coordinates %v do not fit in 15 bits", ev.Coords)
+	case ev.P != 0 && ev.P != 1:
+		return fmt.Sprintf("polarity %d is not 0 or 1", ev.P)
+	case ev.Ts < 0 || ev.Ts>>timestampBits > 0x7FFFFFFF:
+		return fmt.Sprintf("timestamp %d does not fit in 62 bits", ev.Ts)
+	}
+	return ""
+}
+
+// ReadEvents read polarity events in the AEDAT 3.1 format from file
+func (a Aedat3) ReadEvents() (event.EventCapture, error) {
+	f, err := os.Open(a.FilePath)
+	if err != nil {
+		return event.EventCapture{}, err
+	}
+
+	defer f.Close()
+
+	return a.Decode(f)
+}
+
+// Decode reads polarity events in the AEDAT 3.1 format from src.
+// In Strict mode, a truncated last packet is reported as format.ErrTruncated.
+func (a Aedat3) Decode(src io.Reader) (event.EventCapture, error) {
+	r := bufio.NewReader(src)
+	offset, err := a.skipHeader(r)
+	if err != nil {
+		return event.EventCapture{}, err
+	}
+
+	ev := []event.Event{}
+	mX, mY := 0, 0
+	data := make([]byte, polarityEventSize)
+
+	for {
+		h := packetHeader{}
+		err := binary.Read(r, binary.LittleEndian, &h)
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
+			if err := truncated(a.Mode, offset, "incomplete packet header"); err != nil {
+				return event.EventCapture{}, err
+			}
+			break
+		}
+		if err != nil {
+			return event.EventCapture{}, err
+		}
+		offset += packetHeaderSize
+
+		size := int64(h.EventSize) * int64(h.EventCapacity)
+		if size < 0 || h.EventNumber < 0 || h.EventNumber > h.EventCapacity {
+			return event.EventCapture{}, format.NewError(format.ErrBadHeader, offset-packetHeaderSize, "invalid packet size")
+		}
+		if h.EventType != polarityEventType || h.EventSize != polarityEventSize {
+			if n, err := io.CopyN(io.Discard, r, size); err != nil {
+				if err != io.EOF {
+					return event.EventCapture{}, err
+				}
+				if err := truncated(a.Mode, offset+n-n%int64(h.EventSize), "incomplete packet"); err != nil {
+					return event.EventCapture{}, err
+				}
+				break
+			}
+			offset += size
+			continue
+		}
+
+		// events are decoded one by one, so a corrupt capacity can not force a large allocation
+		used := int64(h.EventNumber) * polarityEventSize
+		n := int64(0)
+		for ; n < used; n += polarityEventSize {
+			if _, err := io.ReadFull(r, data); err != nil {
+				if err != io.EOF && err != io.ErrUnexpectedEOF {
+					return event.EventCapture{}, err
+				}
+				break
+			}
+			e, valid := a.newEventFromBytes(data, h.EventTSOverflow)
+			if !valid {
+				continue
+			}
+			if e.Coords.X > mX {
+				mX = e.Coords.X
+			}
+			if e.Coords.Y > mY {
+				mY = e.Coords.Y
+			}
+			ev = append(ev, e)
+		}
+		if n == used {
+			var m int64
+			m, err = io.CopyN(io.Discard, r, size-used)
+			if err != nil && err != io.EOF {
+				return event.EventCapture{}, err
+			}
+			n += m - m%polarityEventSize
+		}
+		if n < size {
+			if err := truncated(a.Mode, offset+n, "incomplete packet"); err != nil {
+				return event.EventCapture{}, err
+			}
+			break
+		}
+		offset += size
+	}
+
+	return event.EventCapture{Events: ev, Width: mX + 1, Height: mY + 1}, nil
+}
+
+func (a Aedat3) writePacket(w *countingWriter, ev []event.Event, overflow int32) error {
+	h := packetHeader{
+		EventType:       polarityEventType,
+		EventSize:       polarityEventSize,
+		EventTSOffset:   4,
+		EventTSOverflow: overflow,
+		EventCapacity:   int32(len(ev)),
+		EventNumber:     int32(len(ev)),
+		EventValid:      int32(len(ev)),
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, h); err != nil {
+		return err
+	}
+	for _, e := range ev {
+		if detail := a.checkEvent(e); detail != "" {
+			return format.NewError(format.ErrOutOfRange, w.n, detail)
+		}
+		if _, err := w.Write(a.eventToBytes(e)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteEvents will write events to file in the AEDAT 3.1 format
+func (a Aedat3) WriteEvents(evCap event.EventCapture) error {
+	f, err := os.Create(a.FilePath)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if err := a.Encode(f, evCap); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Encode writes events to dst in the AEDAT 3.1 format as polarity event packets.
+// Events are expected to be sorted by timestamp. Events which do not fit the format are reported as
+// format.ErrOutOfRange in Strict mode, and skipped in Lenient mode.
+func (a Aedat3) Encode(dst io.Writer, evCap event.EventCapture) error {
+	events := evCap.Events
+	if a.Mode == format.Lenient {
+		events = keep(events, a.checkEvent)
+	}
+
+	bw := bufio.NewWriter(dst)
+	w := &countingWriter{w: bw}
+	if _, err := io.WriteString(w, "#!AER-DAT3.1\r\n#Format: RAW\r\n#Source 1: DVS\r\n#!END-HEADER\r\n"); err != nil {
+		return err
+	}
+
+	start := 0
+	for i := 1; i <= len(events); i++ {
+		overflow := int32(events[start].Ts >> timestampBits)
+		if i < len(events) && i-start < maxPacketEvents && int32(events[i].Ts>>timestampBits) == overflow {
+			continue
+		}
+		if err := a.writePacket(w, events[start:i], overflow); err != nil {
+			return err
+		}
+		start = i
+	}
+
+	return bw.Flush()
+}
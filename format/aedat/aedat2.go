@@ -0,0 +1,170 @@
+package aedat
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/format"
+)
+
+// AddressFormat describes how coordinates and polarity are packed into a jAER event address
+type AddressFormat struct {
+	XMask, XShift uint32
+	YMask, YShift uint32
+	PMask, PShift uint32
+}
+
+// DVS128Address is the address format of DVS128 recordings, such as CIFAR10-DVS
+var DVS128Address = AddressFormat{
+	XMask: 0x00FE, XShift: 1,
+	YMask: 0x7F00, YShift: 8,
+	PMask: 0x0001, PShift: 0,
+}
+
+// Aedat2 implements reading and writing of jAER AEDAT 2.0 files, in which events are stored as big endian
+// pairs of 32 bit address and timestamp after the ASCII header. Addresses are decoded with Address.
+// Some datasets, such as CIFAR10-DVS, use this layout with an AEDAT 3.1 header.
+type Aedat2 struct {
+	FilePath string
+	Address  AddressFormat
+	Mode     format.Mode
+}
+
+func (a Aedat2) newEventFromBytes(data []byte) event.Event {
+	address := binary.BigEndian.Uint32(data[:4])
+	ts := int(binary.BigEndian.Uint32(data[4:]))
+	f := a.Address
+
+	return event.Event{
+		Coords: event.Point2D{X: int((address & f.XMask) >> f.XShift), Y: int((address & f.YMask) >> f.YShift)},
+		P:      int((address & f.PMask) >> f.PShift),
+		Ts:     ts,
+	}
+}
+
+func (a Aedat2) eventToBytes(ev event.Event) []byte {
+	data := make([]byte, 8)
+	f := a.Address
+
+	address := (uint32(ev.Coords.X)<<f.XShift)&f.XMask | (uint32(ev.Coords.Y)<<f.YShift)&f.YMask | (uint32(ev.P)<<f.PShift)&f.PMask
+	binary.BigEndian.PutUint32(data[:4], address)
+	binary.BigEndian.PutUint32(data[4:], uint32(ev.Ts))
+
+	return data
+}
+
+// fits returns true if v can be packed with mask and shift
+func fits(v int, mask, shift uint32) bool {
+	return v >= 0 && uint64(v)<<shift&uint64(mask) == uint64(v)<<shift
+}
+
+// checkEvent reports why ev does not fit the address format
+func (a Aedat2) checkEvent(ev event.Event) string {
+	f := a.Address
+	switch {
+	case !fits(ev.Coords.X, f.XMask, f.XShift) || !fits(ev.Coords.Y, f.YMask, f.YShift):
+		return fmt.Sprintf("coordinates %v do not fit the address format", ev.Coords)
+	case !fits(ev.P, f.PMask, f.PShift):
+		return fmt.Sprintf("polarity %d does not fit the address format", ev.P)
+	case ev.Ts < 0 || ev.Ts > 0xFFFFFFFF:
+		return fmt.Sprintf("timestamp %d does not fit in 32 bits", ev.Ts)
+	}
+	return ""
+}
+
+// ReadEvents read events in the AEDAT 2.0 format from file
+func (a Aedat2) ReadEvents() (event.EventCapture, error) {
+	f, err := os.Open(a.FilePath)
+	if err != nil {
+		return event.EventCapture{}, err
+	}
+
+	defer f.Close()
+
+	return a.Decode(f)
+}
+
+// Decode reads events in the AEDAT 2.0 format from src.
+// In Strict mode, a truncated last event is reported as format.ErrTruncated.
+func (a Aedat2) Decode(src io.Reader) (event.EventCapture, error) {
+	r := bufio.NewReader(src)
+	offset, err := (Aedat3{}).skipHeader(r)
+	if err != nil {
+		return event.EventCapture{}, err
+	}
+
+	ev := []event.Event{}
+	mX, mY := 0, 0
+
+	bb := make([]byte, 8)
+	for ; ; offset += 8 {
+		bc, err := io.ReadFull(r, bb)
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
+			if err := truncated(a.Mode, offset, fmt.Sprintf("%d trailing bytes", bc)); err != nil {
+				return event.EventCapture{}, err
+			}
+			break
+		}
+		if err != nil {
+			return event.EventCapture{}, err
+		}
+
+		n := a.newEventFromBytes(bb)
+		if n.Coords.X > mX {
+			mX = n.Coords.X
+		}
+		if n.Coords.Y > mY {
+			mY = n.Coords.Y
+		}
+		ev = append(ev, n)
+	}
+
+	return event.EventCapture{Events: ev, Width: mX + 1, Height: mY + 1}, nil
+}
+
+// WriteEvents will write events to file in the AEDAT 2.0 format
+func (a Aedat2) WriteEvents(evCap event.EventCapture) error {
+	f, err := os.Create(a.FilePath)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if err := a.Encode(f, evCap); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Encode writes events to dst in the AEDAT 2.0 format.
+// Events which do not fit the format are reported as format.ErrOutOfRange in Strict mode, and skipped in
+// Lenient mode.
+func (a Aedat2) Encode(dst io.Writer, evCap event.EventCapture) error {
+	bw := bufio.NewWriter(dst)
+	w := &countingWriter{w: bw}
+	if _, err := io.WriteString(w, "#!AER-DAT2.0\r\n#!END-HEADER\r\n"); err != nil {
+		return err
+	}
+
+	for _, ev := range evCap.Events {
+		if detail := a.checkEvent(ev); detail != "" {
+			if a.Mode == format.Lenient {
+				continue
+			}
+			return format.NewError(format.ErrOutOfRange, w.n, detail)
+		}
+		if _, err := w.Write(a.eventToBytes(ev)); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,120 @@
+package aedat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/format"
+)
+
+var (
+	_ format.Decoder = Aedat3{}
+	_ format.Encoder = Aedat3{}
+	_ format.Decoder = Aedat2{}
+	_ format.Encoder = Aedat2{}
+)
+
+var testEvents = []event.Event{
+	{Coords: event.Point2D{X: 1, Y: 2}, Ts: 10, P: 1},
+	{Coords: event.Point2D{X: 100, Y: 120}, Ts: 20, P: 0},
+	{Coords: event.Point2D{X: 3, Y: 4}, Ts: 1<<timestampBits + 5, P: 1},
+}
+
+func TestAedat3_WriteEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.aedat")
+	a := Aedat3{FilePath: path}
+	if err := a.WriteEvents(event.EventCapture{Events: testEvents}); err != nil {
+		t.Fatalf("Aedat3.WriteEvents() error = %v", err)
+	}
+	evCap, err := a.ReadEvents()
+	if err != nil || !reflect.DeepEqual(evCap.Events, testEvents) {
+		t.Fatalf("Aedat3.ReadEvents() = %v, %v, want %v", evCap.Events, err, testEvents)
+	}
+
+	// dropping the last 3 bytes truncates the last packet
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, data[:len(data)-3], 0644); err != nil {
+		t.Fatal(err)
+	}
+	var fErr *format.Error
+	if _, err := a.ReadEvents(); !errors.Is(err, format.ErrTruncated) || !errors.As(err, &fErr) || fErr.Offset != int64(len(data)-8) {
+		t.Errorf("Aedat3.ReadEvents() error = %v, want %v at offset %v", err, format.ErrTruncated, len(data)-8)
+	}
+	evCap, err = Aedat3{FilePath: path, Mode: format.Lenient}.ReadEvents()
+	if err != nil || !reflect.DeepEqual(evCap.Events, testEvents[:2]) {
+		t.Errorf("Aedat3.ReadEvents() = %v, %v in lenient mode, want %v", evCap.Events, err, testEvents[:2])
+	}
+
+	invalid := append([]event.Event{{Coords: event.Point2D{X: -1, Y: 2}}}, testEvents...)
+	if err := a.WriteEvents(event.EventCapture{Events: invalid}); !errors.Is(err, format.ErrOutOfRange) {
+		t.Errorf("Aedat3.WriteEvents() error = %v, want %v", err, format.ErrOutOfRange)
+	}
+}
+
+func TestAedat2_WriteEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.aedat")
+	events := []event.Event{testEvents[0], {Coords: event.Point2D{X: 127, Y: 127}, Ts: 30, P: 0}}
+
+	a := Aedat2{FilePath: path, Address: DVS128Address}
+	if err := a.WriteEvents(event.EventCapture{Events: events}); err != nil {
+		t.Fatalf("Aedat2.WriteEvents() error = %v", err)
+	}
+	evCap, err := a.ReadEvents()
+	if err != nil || !reflect.DeepEqual(evCap.Events, events) {
+		t.Fatalf("Aedat2.ReadEvents() = %v, %v, want %v", evCap.Events, err, events)
+	}
+
+	invalid := []event.Event{events[0], {Coords: event.Point2D{X: 200, Y: 1}, Ts: 40}}
+	var fErr *format.Error
+	if err := a.WriteEvents(event.EventCapture{Events: invalid}); !errors.Is(err, format.ErrOutOfRange) || !errors.As(err, &fErr) || fErr.Offset != 36 {
+		t.Errorf("Aedat2.WriteEvents() error = %v, want %v at offset 36", err, format.ErrOutOfRange)
+	}
+
+	lenient := Aedat2{FilePath: path, Address: DVS128Address, Mode: format.Lenient}
+	if err := lenient.WriteEvents(event.EventCapture{Events: invalid}); err != nil {
+		t.Fatalf("Aedat2.WriteEvents() error = %v in lenient mode", err)
+	}
+	if evCap, err := lenient.ReadEvents(); err != nil || !reflect.DeepEqual(evCap.Events, invalid[:1]) {
+		t.Errorf("Aedat2.ReadEvents() = %v, %v, want %v", evCap.Events, err, invalid[:1])
+	}
+}
+
+func TestAedat3_Encode(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (Aedat3{}).Encode(&buf, event.EventCapture{Events: testEvents}); err != nil {
+		t.Fatalf("Aedat3.Encode() error = %v", err)
+	}
+
+	evCap, err := Aedat3{}.Decode(&buf)
+	if err != nil || !reflect.DeepEqual(evCap.Events, testEvents) {
+		t.Errorf("Aedat3.Decode() = %v, %v, want %v", evCap.Events, err, testEvents)
+	}
+}
+
+func TestAedat3_DecodeCapacity(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (Aedat3{}).Encode(&buf, event.EventCapture{Events: testEvents[:1]}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a corrupt header claiming a huge capacity after the single event of the packet
+	data := buf.Bytes()
+	header := len(data) - packetHeaderSize - polarityEventSize
+	binary.LittleEndian.PutUint32(data[header+16:], math.MaxInt32)
+
+	var fErr *format.Error
+	if _, err := (Aedat3{}).Decode(bytes.NewReader(data)); !errors.Is(err, format.ErrTruncated) || !errors.As(err, &fErr) || fErr.Offset != int64(len(data)) {
+		t.Errorf("Aedat3.Decode() error = %v, want %v at offset %v", err, format.ErrTruncated, len(data))
+	}
+	evCap, err := Aedat3{Mode: format.Lenient}.Decode(bytes.NewReader(data))
+	if err != nil || !reflect.DeepEqual(evCap.Events, testEvents[:1]) {
+		t.Errorf("Aedat3.Decode() = %v, %v in lenient mode, want %v", evCap.Events, err, testEvents[:1])
+	}
+}
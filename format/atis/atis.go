@@ -1,17 +1,33 @@
 package atis
 
 import (
-	"math"
+	"bufio"
+	"fmt"
+	"io"
 	"os"
 
 	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/format"
+)
+
+const (
+	// overflowRow is the y coordinate of rows which mark a timestamp overflow instead of an event
+	overflowRow = 240
+	// overflowStep is added to the timestamps of all events following an overflow row
+	overflowStep = 1 << 13
+	// tsRange is the range of the 23 bits timestamp of a row
+	tsRange = 1 << 23
+	// rowSize is the size of a row, in bytes
+	rowSize = 5
 )
 
 // Aer implements ATIS AER format reading and writing
 type Aer struct {
 	FilePath string
+	Mode     format.Mode
 }
 
+// newEventFromBytes decodes a row, without the overflow offset of the previous rows
 func (a Aer) newEventFromBytes(data []byte) event.Event {
 	x := int(data[0])
 	y := int(data[1])
@@ -21,10 +37,6 @@ func (a Aer) newEventFromBytes(data []byte) event.Event {
 	ts := ts1 + ts2 + ts3
 	p := (int(data[2]) & 128) >> 7
 
-	if y == 240 {
-		ts += int(math.Pow(2, 13))
-	}
-
 	return event.Event{
 		Coords: event.Point2D{X: x, Y: y},
 		P:      p,
@@ -32,17 +44,14 @@ func (a Aer) newEventFromBytes(data []byte) event.Event {
 	}
 }
 
-func (a Aer) eventToBytes(ev event.Event) []byte {
+// eventToBytes encodes ev as a row, subtracting the overflow offset of the previous rows from its timestamp
+func (a Aer) eventToBytes(ev event.Event, offset int) []byte {
 	x := ev.Coords.X
 	y := ev.Coords.Y
 
-	ts := ev.Ts
+	ts := ev.Ts - offset
 
-	if y == 240 {
-		ts -= int(math.Pow(2, 13))
-	}
-
-	data := make([]byte, 5)
+	data := make([]byte, rowSize)
 
 	data[0] = byte(x)
 	data[1] = byte(y)
@@ -55,6 +64,21 @@ func (a Aer) eventToBytes(ev event.Event) []byte {
 	return data
 }
 
+// checkEvent reports why ev cannot be written after the overflow offset of the previous rows
+func (a Aer) checkEvent(ev event.Event, offset int) string {
+	switch {
+	case ev.Coords.X < 0 || ev.Coords.X > 255:
+		return fmt.Sprintf("x %d does not fit in 8 bits", ev.Coords.X)
+	case ev.Coords.Y < 0 || ev.Coords.Y >= overflowRow:
+		return fmt.Sprintf("y %d is not lower than %d", ev.Coords.Y, overflowRow)
+	case ev.P != 0 && ev.P != 1:
+		return fmt.Sprintf("polarity %d is not 0 or 1", ev.P)
+	case ev.Ts < offset:
+		return fmt.Sprintf("timestamp %d is earlier than the previous events", ev.Ts)
+	}
+	return ""
+}
+
 // ReadEvents read events in the ATIS AER format from file
 func (a Aer) ReadEvents() (event.EventCapture, error) {
 	f, err := os.Open(a.FilePath)
@@ -64,28 +88,49 @@ func (a Aer) ReadEvents() (event.EventCapture, error) {
 
 	defer f.Close()
 
+	return a.Decode(f)
+}
+
+// Decode reads events in the ATIS AER format from r.
+// Overflow rows are not returned as events, and add 2^13 to the timestamps of all following events.
+// In Strict mode, a truncated last row is reported as format.ErrTruncated.
+func (a Aer) Decode(r io.Reader) (event.EventCapture, error) {
+	br := bufio.NewReader(r)
 	ev := []event.Event{}
 
-	bb := make([]byte, 5)
-	bc := 5
+	bb := make([]byte, rowSize)
 
 	mX, mY := 0, 0
+	tsOffset := 0
 
-	for bc == 5 {
-		bc, err = f.Read(bb)
-		if bc == 5 && err == nil {
-			n := a.newEventFromBytes(bb)
-			if n.Coords.Y == 240 {
-				continue
-			}
-			if n.Coords.X > mX {
-				mX = n.Coords.X
-			}
-			if n.Coords.Y > mY {
-				mY = n.Coords.Y
+	for offset := int64(0); ; offset += rowSize {
+		bc, err := io.ReadFull(br, bb)
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
+			if a.Mode == format.Lenient {
+				break
 			}
-			ev = append(ev, n)
+			return event.EventCapture{}, format.NewError(format.ErrTruncated, offset, fmt.Sprintf("%d trailing bytes", bc))
+		}
+		if err != nil {
+			return event.EventCapture{}, err
+		}
+
+		n := a.newEventFromBytes(bb)
+		if n.Coords.Y == overflowRow {
+			tsOffset += overflowStep
+			continue
 		}
+		n.Ts += tsOffset
+		if n.Coords.X > mX {
+			mX = n.Coords.X
+		}
+		if n.Coords.Y > mY {
+			mY = n.Coords.Y
+		}
+		ev = append(ev, n)
 	}
 
 	return event.EventCapture{
@@ -98,16 +143,52 @@ func (a Aer) ReadEvents() (event.EventCapture, error) {
 // WriteEvents will write events to file in the ATIS AER format
 func (a Aer) WriteEvents(evCap event.EventCapture) error {
 	f, err := os.Create(a.FilePath)
+	if err != nil {
+		return err
+	}
 
 	defer f.Close()
-	if err != nil {
+
+	if err := a.Encode(f, evCap); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Encode writes events to w in the ATIS AER format.
+// Overflow rows are written before events whose timestamps exceed the 23 bits range, so events are expected to
+// be sorted by timestamp. Events which do not fit the format are reported as format.ErrOutOfRange in Strict
+// mode, and skipped in Lenient mode.
+func (a Aer) Encode(w io.Writer, evCap event.EventCapture) error {
+	bw := bufio.NewWriter(w)
+
+	offset := int64(0)
+	write := func(data []byte) error {
+		bc, err := bw.Write(data)
+		offset += int64(bc)
 		return err
 	}
 
+	tsOffset := 0
+	overflow := a.eventToBytes(event.Event{Coords: event.Point2D{Y: overflowRow}}, 0)
+
 	for _, ev := range evCap.Events {
+		if detail := a.checkEvent(ev, tsOffset); detail != "" {
+			if a.Mode == format.Lenient {
+				continue
+			}
+			return format.NewError(format.ErrOutOfRange, offset, detail)
+		}
+		for ev.Ts-tsOffset >= tsRange {
+			if err := write(overflow); err != nil {
+				return err
+			}
+			tsOffset += overflowStep
+		}
 
-		data := a.eventToBytes(ev)
-		f.Write(data)
+		if err := write(a.eventToBytes(ev, tsOffset)); err != nil {
+			return err
+		}
 	}
-	return nil
+	return bw.Flush()
 }
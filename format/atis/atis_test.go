@@ -0,0 +1,144 @@
+package atis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/format"
+)
+
+func TestAer_ReadEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overflow.bin")
+	rows := []byte{
+		3, 4, 0x80, 0, 100,
+		0, 240, 0, 0, 0,
+		5, 6, 0, 0, 50,
+		0, 240, 0x7f, 0xff, 0xff,
+		7, 8, 0x80, 1, 0,
+	}
+	if err := os.WriteFile(path, rows, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	evCap, err := Aer{FilePath: path}.ReadEvents()
+	if err != nil {
+		t.Fatalf("Aer.ReadEvents() error = %v", err)
+	}
+
+	want := []event.Event{
+		{Coords: event.Point2D{X: 3, Y: 4}, Ts: 100, P: 1},
+		{Coords: event.Point2D{X: 5, Y: 6}, Ts: 50 + 1<<13, P: 0},
+		{Coords: event.Point2D{X: 7, Y: 8}, Ts: 256 + 2<<13, P: 1},
+	}
+	if !reflect.DeepEqual(evCap.Events, want) {
+		t.Errorf("Aer.ReadEvents() = %v, want %v", evCap.Events, want)
+	}
+}
+
+func TestAer_WriteEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roundtrip.bin")
+	events := []event.Event{
+		{Coords: event.Point2D{X: 1, Y: 2}, Ts: 10, P: 1},
+		{Coords: event.Point2D{X: 3, Y: 4}, Ts: tsRange - 1, P: 0},
+		{Coords: event.Point2D{X: 5, Y: 6}, Ts: tsRange, P: 1},
+		{Coords: event.Point2D{X: 7, Y: 8}, Ts: 3*tsRange + 12345, P: 0},
+	}
+
+	a := Aer{FilePath: path}
+	if err := a.WriteEvents(event.EventCapture{Events: events}); err != nil {
+		t.Fatalf("Aer.WriteEvents() error = %v", err)
+	}
+	evCap, err := a.ReadEvents()
+	if err != nil {
+		t.Fatalf("Aer.ReadEvents() error = %v", err)
+	}
+	if !reflect.DeepEqual(evCap.Events, events) {
+		t.Errorf("Aer.ReadEvents() = %v, want %v", evCap.Events, events)
+	}
+
+	tests := []struct {
+		name   string
+		events []event.Event
+		offset int64
+	}{
+		{name: "Test unsorted timestamps", events: []event.Event{events[3], events[0]}, offset: 5*2050 + 5}, // 2050 overflow rows and a row precede the second event
+		{name: "Test overflow row coordinate", events: []event.Event{events[0], {Coords: event.Point2D{X: 1, Y: 240}}}, offset: 5},
+		{name: "Test wide coordinate", events: []event.Event{{Coords: event.Point2D{X: 256, Y: 1}}}, offset: 0},
+		{name: "Test invalid polarity", events: []event.Event{{Coords: event.Point2D{X: 1, Y: 1}, P: -1}}, offset: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := a.WriteEvents(event.EventCapture{Events: tt.events})
+			var fErr *format.Error
+			if !errors.Is(err, format.ErrOutOfRange) || !errors.As(err, &fErr) || fErr.Offset != tt.offset {
+				t.Errorf("Aer.WriteEvents() error = %v, want %v at offset %v", err, format.ErrOutOfRange, tt.offset)
+			}
+
+			lenient := Aer{FilePath: path, Mode: format.Lenient}
+			if err := lenient.WriteEvents(event.EventCapture{Events: tt.events}); err != nil {
+				t.Fatalf("Aer.WriteEvents() error = %v in lenient mode", err)
+			}
+			evCap, err := lenient.ReadEvents()
+			if err != nil || len(evCap.Events) != len(tt.events)-1 {
+				t.Errorf("Aer.ReadEvents() = %v, %v, want the events which fit", evCap.Events, err)
+			}
+		})
+	}
+}
+
+func TestAer_ReadEvents_truncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "truncated.bin")
+	if err := os.WriteFile(path, []byte{3, 4, 0x80, 0, 100, 5, 6}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Aer{FilePath: path}.ReadEvents()
+	var fErr *format.Error
+	if !errors.Is(err, format.ErrTruncated) || !errors.As(err, &fErr) || fErr.Offset != 5 {
+		t.Errorf("Aer.ReadEvents() error = %v, want %v at offset 5", err, format.ErrTruncated)
+	}
+
+	evCap, err := Aer{FilePath: path, Mode: format.Lenient}.ReadEvents()
+	if err != nil || len(evCap.Events) != 1 {
+		t.Errorf("Aer.ReadEvents() = %v, %v in lenient mode, want 1 event", evCap.Events, err)
+	}
+}
+
+var (
+	_ format.Decoder = Aer{}
+	_ format.Encoder = Aer{}
+)
+
+func TestAer_Encode(t *testing.T) {
+	events := []event.Event{
+		{Coords: event.Point2D{X: 1, Y: 2}, Ts: 10, P: 1},
+		{Coords: event.Point2D{X: 3, Y: 4}, Ts: tsRange + 7, P: 0},
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := (Aer{}).Encode(zw, event.EventCapture{Events: events}); err != nil {
+		t.Fatalf("Aer.Encode() error = %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	evCap, err := Aer{}.Decode(zr)
+	if err != nil {
+		t.Fatalf("Aer.Decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(evCap.Events, events) {
+		t.Errorf("Aer.Decode() = %v, want %v", evCap.Events, events)
+	}
+}
@@ -1,4 +1,64 @@
 package format
 
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ffardo/go-event-vision"
+)
+
 type Format interface {
 }
+
+// Decoder reads an event capture from a stream, such as a file, a gzip stream, an archive entry or stdin
+type Decoder interface {
+	Decode(r io.Reader) (event.EventCapture, error)
+}
+
+// Encoder writes an event capture to a stream
+type Encoder interface {
+	Encode(w io.Writer, evCap event.EventCapture) error
+}
+
+// Errors reported by the codecs, wrapped in an *Error with the byte offset where they were found
+var (
+	ErrTruncated  = errors.New("Truncated data")
+	ErrBadHeader  = errors.New("Invalid header")
+	ErrOutOfRange = errors.New("Value out of range")
+)
+
+// Error is a codec error at a byte offset of the data. It unwraps to one of the sentinel errors, so it can be
+// inspected with errors.Is.
+type Error struct {
+	Err    error  // sentinel error
+	Offset int64  // byte offset of the data being read or written
+	Detail string // optional description
+}
+
+// NewError creates an *Error
+func NewError(err error, offset int64, detail string) *Error {
+	return &Error{Err: err, Offset: offset, Detail: detail}
+}
+
+func (e *Error) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("%v at offset %d", e.Err, e.Offset)
+	}
+	return fmt.Sprintf("%v at offset %d: %s", e.Err, e.Offset, e.Detail)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Mode selects how codecs handle corrupt data
+type Mode int
+
+const (
+	// Strict reports truncated data and values which do not fit the format as errors
+	Strict Mode = iota
+	// Lenient ignores truncated data at the end of a file, returning the events decoded before it, and skips
+	// events which do not fit the format when writing. Invalid headers are still reported.
+	Lenient
+)
@@ -0,0 +1,23 @@
+package format
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	err := fmt.Errorf("reading sample: %w", NewError(ErrTruncated, 42, "3 trailing bytes"))
+
+	if !errors.Is(err, ErrTruncated) || errors.Is(err, ErrBadHeader) {
+		t.Errorf("errors.Is() does not match the sentinel of %v", err)
+	}
+
+	var fErr *Error
+	if !errors.As(err, &fErr) || fErr.Offset != 42 {
+		t.Fatalf("errors.As() = %v, want offset 42", fErr)
+	}
+	if got, want := fErr.Error(), "Truncated data at offset 42: 3 trailing bytes"; got != want {
+		t.Errorf("Error.Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,385 @@
+/*
+matlab implements reading and writing of numeric arrays in MATLAB level 5 MAT-files, as saved by MATLAB
+versions 5 to 7, including compressed variables. MAT 7.3 files are HDF5 containers and are not supported.
+Variables of non numeric classes, such as cells, structs, chars and sparse arrays, are skipped on read.
+*/
+package matlab
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/ffardo/go-event-vision/format"
+)
+
+// data types
+const (
+	miINT8       = 1
+	miUINT8      = 2
+	miINT16      = 3
+	miUINT16     = 4
+	miINT32      = 5
+	miUINT32     = 6
+	miSINGLE     = 7
+	miDOUBLE     = 9
+	miINT64      = 12
+	miUINT64     = 13
+	miMATRIX     = 14
+	miCOMPRESSED = 15
+)
+
+// array classes
+const (
+	mxDouble = 6
+	mxUint64 = 15
+
+	headerSize = 128
+)
+
+// Variable is a numeric MATLAB array. Data holds the real part of the array in column-major order, as in MATLAB.
+type Variable struct {
+	Dims []int
+	Data []float64
+}
+
+// Ints returns the values of the variable truncated to integers
+func (v Variable) Ints() []int {
+	dst := make([]int, len(v.Data))
+	for i, d := range v.Data {
+		dst[i] = int(d)
+	}
+	return dst
+}
+
+// ReadFile reads the numeric variables of a MAT-file, keyed by variable name
+func ReadFile(path string) (map[string]Variable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Read reads the numeric variables of a MAT-file from r, keyed by variable name
+func Read(r io.Reader) (map[string]Variable, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < headerSize {
+		return nil, format.NewError(format.ErrTruncated, int64(len(data)), "incomplete MAT-file header")
+	}
+
+	var order binary.ByteOrder
+	switch string(data[126:128]) {
+	case "IM":
+		order = binary.LittleEndian
+	case "MI":
+		order = binary.BigEndian
+	default:
+		return nil, format.NewError(format.ErrBadHeader, 126, "invalid MAT-file endian indicator")
+	}
+
+	if order.Uint16(data[124:126]) != 0x0100 {
+		return nil, format.NewError(format.ErrBadHeader, 124, "unsupported MAT-file version, only level 5 files are supported")
+	}
+
+	vars := map[string]Variable{}
+	if err := readElements(data[headerSize:], headerSize, order, vars); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// element reads the data element at the start of data, found at offset, and returns its type, its payload and
+// the size of the whole element including padding
+func element(data []byte, offset int64, order binary.ByteOrder) (typ uint32, payload []byte, size int, err error) {
+	if len(data) < 8 {
+		return 0, nil, 0, format.NewError(format.ErrTruncated, offset, "incomplete element tag")
+	}
+
+	// small data elements pack the size and type in 4 bytes, followed by up to 4 bytes of data
+	if tag := order.Uint32(data); tag>>16 != 0 {
+		n := int(tag >> 16)
+		if n > 4 {
+			return 0, nil, 0, format.NewError(format.ErrBadHeader, offset, "invalid small element size")
+		}
+		return tag & 0xFFFF, data[4 : 4+n], 8, nil
+	}
+
+	typ = order.Uint32(data)
+	n := int(order.Uint32(data[4:]))
+	if n < 0 || n > len(data)-8 {
+		return 0, nil, 0, format.NewError(format.ErrTruncated, offset, "incomplete element")
+	}
+
+	size = 8 + n
+	// compressed elements are not padded
+	if typ != miCOMPRESSED {
+		size += (8 - n%8) % 8
+	}
+	if size > len(data) {
+		size = len(data)
+	}
+	return typ, data[8 : 8+n], size, nil
+}
+
+// readElements reads the elements of data, found at offset
+func readElements(data []byte, offset int64, order binary.ByteOrder, vars map[string]Variable) error {
+	for len(data) > 0 {
+		typ, payload, size, err := element(data, offset, order)
+		if err != nil {
+			return err
+		}
+		start := offset
+		data, offset = data[size:], offset+int64(size)
+
+		switch typ {
+		case miCOMPRESSED:
+			zr, err := zlib.NewReader(bytes.NewReader(payload))
+			if err != nil {
+				return format.NewError(format.ErrBadHeader, start, "invalid compressed element")
+			}
+			inflated, err := io.ReadAll(zr)
+			if err == io.ErrUnexpectedEOF {
+				return format.NewError(format.ErrTruncated, start, "incomplete compressed element")
+			}
+			if err != nil {
+				return err
+			}
+			if err := readElements(inflated, 0, order, vars); err != nil {
+				return compressedError(err, start)
+			}
+		case miMATRIX:
+			name, v, ok, err := readMatrix(payload, start+8, order)
+			if err != nil {
+				return err
+			}
+			if ok {
+				vars[name] = v
+			}
+		}
+	}
+	return nil
+}
+
+// compressedError reports an error found in the inflated data of a compressed element at the offset of the
+// element, since inflated offsets do not match the file
+func compressedError(err error, offset int64) error {
+	var fErr *format.Error
+	if !errors.As(err, &fErr) {
+		return err
+	}
+	return format.NewError(fErr.Err, offset, fmt.Sprintf("%s at inflated offset %d", fErr.Detail, fErr.Offset))
+}
+
+// readMatrix reads the payload of a miMATRIX element, found at offset. It returns false for arrays of non numeric
+// classes.
+func readMatrix(data []byte, offset int64, order binary.ByteOrder) (string, Variable, bool, error) {
+	// empty arrays may be stored without subelements
+	if len(data) == 0 {
+		return "", Variable{}, false, nil
+	}
+
+	sub := make([][]byte, 0, 4)
+	types := make([]uint32, 0, 4)
+	offsets := make([]int64, 0, 4)
+	for len(data) > 0 && len(sub) < 4 {
+		typ, payload, size, err := element(data, offset, order)
+		if err != nil {
+			return "", Variable{}, false, err
+		}
+		sub = append(sub, payload)
+		types = append(types, typ)
+		offsets = append(offsets, offset)
+		data, offset = data[size:], offset+int64(size)
+	}
+
+	if len(sub) < 3 || types[0] != miUINT32 || len(sub[0]) < 8 || types[1] != miINT32 {
+		return "", Variable{}, false, format.NewError(format.ErrBadHeader, offsets[0], "invalid array subelements")
+	}
+
+	name := string(sub[2])
+	class := order.Uint32(sub[0]) & 0xFF
+	if class < mxDouble || class > mxUint64 || len(sub) < 4 {
+		return name, Variable{}, false, nil
+	}
+
+	dims := make([]int, len(sub[1])/4)
+	count := 1
+	for i := range dims {
+		dims[i] = int(int32(order.Uint32(sub[1][i*4:])))
+		count *= dims[i]
+	}
+
+	values, ok := decode(types[3], sub[3], order)
+	if !ok {
+		return "", Variable{}, false, format.NewError(format.ErrBadHeader, offsets[3], "unsupported data type")
+	}
+	if len(values) != count {
+		return "", Variable{}, false, format.NewError(format.ErrBadHeader, offsets[3], "array size does not match its dimensions")
+	}
+
+	return name, Variable{Dims: dims, Data: values}, true, nil
+}
+
+// decode converts numeric data of any MAT-file type to float64. It returns false for unsupported types.
+func decode(typ uint32, data []byte, order binary.ByteOrder) ([]float64, bool) {
+	var size int
+	switch typ {
+	case miINT8, miUINT8:
+		size = 1
+	case miINT16, miUINT16:
+		size = 2
+	case miINT32, miUINT32, miSINGLE:
+		size = 4
+	case miDOUBLE, miINT64, miUINT64:
+		size = 8
+	default:
+		return nil, false
+	}
+
+	dst := make([]float64, len(data)/size)
+	for i := range dst {
+		b := data[i*size:]
+		switch typ {
+		case miINT8:
+			dst[i] = float64(int8(b[0]))
+		case miUINT8:
+			dst[i] = float64(b[0])
+		case miINT16:
+			dst[i] = float64(int16(order.Uint16(b)))
+		case miUINT16:
+			dst[i] = float64(order.Uint16(b))
+		case miINT32:
+			dst[i] = float64(int32(order.Uint32(b)))
+		case miUINT32:
+			dst[i] = float64(order.Uint32(b))
+		case miSINGLE:
+			dst[i] = float64(math.Float32frombits(order.Uint32(b)))
+		case miDOUBLE:
+			dst[i] = math.Float64frombits(order.Uint64(b))
+		case miINT64:
+			dst[i] = float64(int64(order.Uint64(b)))
+		case miUINT64:
+			dst[i] = float64(order.Uint64(b))
+		}
+	}
+	return dst, true
+}
+
+// appendElement appends a data element with its tag and padding to dst
+func appendElement(dst []byte, typ uint32, payload []byte) []byte {
+	tag := make([]byte, 8)
+	binary.LittleEndian.PutUint32(tag, typ)
+	binary.LittleEndian.PutUint32(tag[4:], uint32(len(payload)))
+
+	dst = append(dst, tag...)
+	dst = append(dst, payload...)
+	return append(dst, make([]byte, (8-len(payload)%8)%8)...)
+}
+
+func matrixElement(name string, v Variable) ([]byte, error) {
+	count := 1
+	for _, d := range v.Dims {
+		count *= d
+	}
+	if len(v.Dims) < 2 || count != len(v.Data) {
+		return nil, errors.New("Invalid variable dimensions")
+	}
+
+	flags := make([]byte, 8)
+	binary.LittleEndian.PutUint32(flags, mxDouble)
+
+	dims := make([]byte, 4*len(v.Dims))
+	for i, d := range v.Dims {
+		binary.LittleEndian.PutUint32(dims[i*4:], uint32(d))
+	}
+
+	values := make([]byte, 8*len(v.Data))
+	for i, d := range v.Data {
+		binary.LittleEndian.PutUint64(values[i*8:], math.Float64bits(d))
+	}
+
+	content := appendElement(nil, miUINT32, flags)
+	content = appendElement(content, miINT32, dims)
+	content = appendElement(content, miINT8, []byte(name))
+	content = appendElement(content, miDOUBLE, values)
+
+	return appendElement(nil, miMATRIX, content), nil
+}
+
+// Write writes variables to w as a little endian MAT-file with compressed double arrays
+func Write(w io.Writer, vars map[string]Variable) error {
+	header := bytes.Repeat([]byte{' '}, headerSize)
+	copy(header, "MATLAB 5.0 MAT-file, written by go-event-vision")
+	copy(header[116:124], make([]byte, 8))
+	binary.LittleEndian.PutUint16(header[124:], 0x0100)
+	copy(header[126:], "IM")
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name == "" {
+			return errors.New("Invalid variable name")
+		}
+
+		matrix, err := matrixElement(name, vars[name])
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		if _, err := zw.Write(matrix); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+
+		tag := make([]byte, 8)
+		binary.LittleEndian.PutUint32(tag, miCOMPRESSED)
+		binary.LittleEndian.PutUint32(tag[4:], uint32(buf.Len()))
+		if _, err := w.Write(tag); err != nil {
+			return err
+		}
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteFile writes variables to a MAT-file
+func WriteFile(path string, vars map[string]Variable) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if err := Write(f, vars); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,108 @@
+package matlab
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision/format"
+)
+
+func TestWriteRead(t *testing.T) {
+	vars := map[string]Variable{
+		"x":  {Dims: []int{3, 1}, Data: []float64{1, 2, 239}},
+		"ts": {Dims: []int{3, 1}, Data: []float64{10, 20, 1e9}},
+		"m":  {Dims: []int{2, 2}, Data: []float64{-1.5, 0, 2.25, 3}},
+	}
+
+	b := &bytes.Buffer{}
+	if err := Write(b, vars); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	got, err := Read(b)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, vars) {
+		t.Errorf("Read() = %v, want %v", got, vars)
+	}
+}
+
+func TestReadBigEndian(t *testing.T) {
+	be := binary.BigEndian
+
+	element := func(typ uint32, payload []byte) []byte {
+		tag := make([]byte, 8)
+		be.PutUint32(tag, typ)
+		be.PutUint32(tag[4:], uint32(len(payload)))
+		b := append(tag, payload...)
+		return append(b, make([]byte, (8-len(payload)%8)%8)...)
+	}
+
+	flags := make([]byte, 8)
+	be.PutUint32(flags, 10) // mxINT16_CLASS
+	dims := make([]byte, 8)
+	be.PutUint32(dims, 1)
+	be.PutUint32(dims[4:], 3)
+	// small data element holding the name
+	name := []byte{0, 3, 0, 1, 'p', 'o', 'l', 0}
+	values := []byte{0, 1, 0xFF, 0xFF, 0, 0}
+
+	matrix := element(miUINT32, flags)
+	matrix = append(matrix, element(miINT32, dims)...)
+	matrix = append(matrix, name...)
+	matrix = append(matrix, element(miINT16, values)...)
+
+	// char arrays are skipped
+	be.PutUint32(flags, 4)
+	chars := element(miUINT32, flags)
+	chars = append(chars, element(miINT32, dims)...)
+	chars = append(chars, []byte{0, 3, 0, 1, 's', 't', 'r', 0}...)
+	chars = append(chars, element(miUINT16, []byte{0, 'a', 0, 'b', 0, 'c'})...)
+
+	header := bytes.Repeat([]byte{' '}, headerSize)
+	be.PutUint16(header[124:], 0x0100)
+	copy(header[126:], "MI")
+
+	data := append(header, element(miMATRIX, matrix)...)
+	data = append(data, element(miMATRIX, chars)...)
+
+	got, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	want := map[string]Variable{"pol": {Dims: []int{1, 3}, Data: []float64{1, -1, 0}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %v, want %v", got, want)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	header := bytes.Repeat([]byte{' '}, headerSize)
+	copy(header[126:], "IM")
+
+	compressed := append([]byte{}, header[:124]...)
+	compressed = append(compressed, 0, 1, 'I', 'M', miCOMPRESSED, 0, 0, 0, 4, 0, 0, 0, 1, 2, 3, 4)
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{name: "Test truncated header", data: header[:100], want: format.ErrTruncated},
+		{name: "Test MAT 7.3 file", data: append(append([]byte{}, header[:124]...), 0, 2, 'I', 'M'), want: format.ErrBadHeader},
+		{name: "Test truncated element", data: append(append(append([]byte{}, header[:124]...), 0, 1, 'I', 'M'), 14, 0, 0, 0, 64, 0, 0, 0), want: format.ErrTruncated},
+		{name: "Test invalid compressed element", data: compressed, want: format.ErrBadHeader},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(bytes.NewReader(tt.data)); !errors.Is(err, tt.want) {
+				t.Errorf("Read() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
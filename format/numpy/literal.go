@@ -0,0 +1,153 @@
+package numpy
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// literalParser parses the subset of Python literals used in npy headers: dicts, lists, tuples,
+// quoted strings, integers and booleans. Dicts are returned as map[string]interface{} and both
+// lists and tuples as []interface{}.
+type literalParser struct {
+	s   string
+	pos int
+}
+
+func parseLiteral(s string) (interface{}, error) {
+	p := &literalParser{s: s}
+	v, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos != len(p.s) {
+		return nil, errors.New("Invalid npy header")
+	}
+	return v, nil
+}
+
+func (p *literalParser) skipSpaces() {
+	for p.pos < len(p.s) && strings.ContainsRune(" \t\r\n", rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *literalParser) peek() byte {
+	p.skipSpaces()
+	if p.pos >= len(p.s) {
+		return 0
+	}
+	return p.s[p.pos]
+}
+
+func (p *literalParser) value() (interface{}, error) {
+	switch c := p.peek(); {
+	case c == '{':
+		return p.dict()
+	case c == '[':
+		return p.sequence('[', ']')
+	case c == '(':
+		return p.sequence('(', ')')
+	case c == '\'' || c == '"':
+		return p.str()
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.integer()
+	case strings.HasPrefix(p.s[p.pos:], "True"):
+		p.pos += 4
+		return true, nil
+	case strings.HasPrefix(p.s[p.pos:], "False"):
+		p.pos += 5
+		return false, nil
+	}
+	return nil, errors.New("Invalid npy header")
+}
+
+func (p *literalParser) str() (interface{}, error) {
+	quote := p.s[p.pos]
+	end := strings.IndexByte(p.s[p.pos+1:], quote)
+	if end < 0 {
+		return nil, errors.New("Invalid npy header")
+	}
+	v := p.s[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return v, nil
+}
+
+func (p *literalParser) integer() (interface{}, error) {
+	start := p.pos
+	if p.s[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	v, err := strconv.Atoi(p.s[start:p.pos])
+	// Python 2 long literals
+	if p.pos < len(p.s) && p.s[p.pos] == 'L' {
+		p.pos++
+	}
+	if err != nil {
+		return nil, errors.New("Invalid npy header")
+	}
+	return v, nil
+}
+
+// sequence parses lists and tuples, allowing a trailing comma
+func (p *literalParser) sequence(open, close byte) (interface{}, error) {
+	p.pos++
+	values := []interface{}{}
+	for {
+		if p.peek() == close {
+			p.pos++
+			return values, nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case close:
+		default:
+			return nil, errors.New("Invalid npy header")
+		}
+	}
+}
+
+func (p *literalParser) dict() (interface{}, error) {
+	p.pos++
+	values := map[string]interface{}{}
+	for {
+		if p.peek() == '}' {
+			p.pos++
+			return values, nil
+		}
+		k, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok || p.peek() != ':' {
+			return nil, errors.New("Invalid npy header")
+		}
+		p.pos++
+
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values[key] = v
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			return nil, errors.New("Invalid npy header")
+		}
+	}
+}
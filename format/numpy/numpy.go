@@ -0,0 +1,553 @@
+// numpy implements reading and writing of NumPy .npy arrays and .npz archives.
+// Numeric and boolean dtypes are supported, including structured arrays with named fields
+// such as the event arrays shipped with N-ImageNet or the bounding boxes of Prophesee datasets.
+package numpy
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ffardo/go-event-vision/format"
+)
+
+var magic = []byte("\x93NUMPY")
+
+const maxInt = int(^uint(0) >> 1)
+
+// Field describes a field of an array dtype. Descr is the NumPy type string, such as "<u2", "<f8" or "|b1".
+// Arrays with a simple dtype have a single field with an empty name.
+type Field struct {
+	Name  string
+	Descr string
+
+	kind   byte // 'i', 'u', 'f' or 'b'
+	size   int
+	offset int
+	order  binary.ByteOrder
+}
+
+// Array is an in-memory NumPy array. Items are stored in file order, which is row-major unless FortranOrder is set.
+type Array struct {
+	Shape        []int
+	Fields       []Field
+	FortranOrder bool
+
+	itemSize int
+	data     []byte
+}
+
+func (f *Field) parseDescr() error {
+	d := f.Descr
+	if d == "" {
+		return errors.New("Empty dtype")
+	}
+
+	f.order = binary.LittleEndian
+	switch d[0] {
+	case '<', '|', '=':
+		d = d[1:]
+	case '>':
+		f.order = binary.BigEndian
+		d = d[1:]
+	}
+
+	if d == "?" {
+		f.kind, f.size = 'b', 1
+		return nil
+	}
+
+	if len(d) < 2 || !strings.ContainsRune("iufb", rune(d[0])) {
+		return fmt.Errorf("Unsupported dtype %q", f.Descr)
+	}
+
+	size := 0
+	if _, err := fmt.Sscanf(d[1:], "%d", &size); err != nil {
+		return fmt.Errorf("Unsupported dtype %q", f.Descr)
+	}
+
+	f.kind, f.size = d[0], size
+	valid := map[byte][]int{'i': {1, 2, 4, 8}, 'u': {1, 2, 4, 8}, 'f': {4, 8}, 'b': {1}}
+	for _, s := range valid[f.kind] {
+		if s == size {
+			return nil
+		}
+	}
+	return fmt.Errorf("Unsupported dtype %q", f.Descr)
+}
+
+func (a *Array) layout() error {
+	a.itemSize = 0
+	for i := range a.Fields {
+		if err := a.Fields[i].parseDescr(); err != nil {
+			return err
+		}
+		a.Fields[i].offset = a.itemSize
+		a.itemSize += a.Fields[i].size
+	}
+	if len(a.Fields) == 0 {
+		return errors.New("Array without fields")
+	}
+
+	// the shape is checked before allocating, so a corrupt header can not panic or overflow the data size
+	n := a.itemSize
+	for _, s := range a.Shape {
+		if s < 0 {
+			return errors.New("Invalid array shape")
+		}
+		if s > 0 && n > maxInt/s {
+			return errors.New("Array too large")
+		}
+		n *= s
+	}
+	return nil
+}
+
+// NewArray creates a zeroed array with the given fields and shape
+func NewArray(fields []Field, shape ...int) (*Array, error) {
+	a := &Array{Shape: shape, Fields: append([]Field{}, fields...)}
+	if err := a.layout(); err != nil {
+		return nil, err
+	}
+	a.data = make([]byte, a.Len()*a.itemSize)
+	return a, nil
+}
+
+// Len returns the number of items of the array, which is the product of its shape
+func (a *Array) Len() int {
+	n := 1
+	for _, s := range a.Shape {
+		n *= s
+	}
+	return n
+}
+
+func (a *Array) field(name string) (Field, error) {
+	for _, f := range a.Fields {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return Field{}, fmt.Errorf("Unknown field %q", name)
+}
+
+func (a *Array) raw(f Field, i int) []byte {
+	start := i*a.itemSize + f.offset
+	return a.data[start : start+f.size]
+}
+
+func (f Field) float(b []byte) float64 {
+	switch f.kind {
+	case 'f':
+		if f.size == 4 {
+			return float64(math.Float32frombits(f.order.Uint32(b)))
+		}
+		return math.Float64frombits(f.order.Uint64(b))
+	case 'u', 'b':
+		return float64(f.uint(b))
+	}
+	return float64(f.int(b))
+}
+
+func (f Field) uint(b []byte) uint64 {
+	switch f.size {
+	case 1:
+		return uint64(b[0])
+	case 2:
+		return uint64(f.order.Uint16(b))
+	case 4:
+		return uint64(f.order.Uint32(b))
+	}
+	return f.order.Uint64(b)
+}
+
+func (f Field) int(b []byte) int64 {
+	switch f.kind {
+	case 'f':
+		return int64(f.float(b))
+	case 'u', 'b':
+		return int64(f.uint(b))
+	}
+	switch f.size {
+	case 1:
+		return int64(int8(b[0]))
+	case 2:
+		return int64(int16(f.order.Uint16(b)))
+	case 4:
+		return int64(int32(f.order.Uint32(b)))
+	}
+	return int64(f.order.Uint64(b))
+}
+
+func (f Field) put(b []byte, i int64, v float64) {
+	if f.kind == 'f' {
+		if f.size == 4 {
+			f.order.PutUint32(b, math.Float32bits(float32(v)))
+		} else {
+			f.order.PutUint64(b, math.Float64bits(v))
+		}
+		return
+	}
+	switch f.size {
+	case 1:
+		b[0] = byte(i)
+	case 2:
+		f.order.PutUint16(b, uint16(i))
+	case 4:
+		f.order.PutUint32(b, uint32(i))
+	default:
+		f.order.PutUint64(b, uint64(i))
+	}
+}
+
+// Int returns field name of item i as an integer. Use an empty name for arrays with a simple dtype.
+func (a *Array) Int(name string, i int) (int64, error) {
+	f, err := a.field(name)
+	if err != nil {
+		return 0, err
+	}
+	return f.int(a.raw(f, i)), nil
+}
+
+// Float returns field name of item i as a float. Use an empty name for arrays with a simple dtype.
+func (a *Array) Float(name string, i int) (float64, error) {
+	f, err := a.field(name)
+	if err != nil {
+		return 0, err
+	}
+	return f.float(a.raw(f, i)), nil
+}
+
+// Ints returns field name of every item as integers, in storage order
+func (a *Array) Ints(name string) ([]int64, error) {
+	f, err := a.field(name)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]int64, a.Len())
+	for i := range values {
+		values[i] = f.int(a.raw(f, i))
+	}
+	return values, nil
+}
+
+// Floats returns field name of every item as floats, in storage order
+func (a *Array) Floats(name string) ([]float64, error) {
+	f, err := a.field(name)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float64, a.Len())
+	for i := range values {
+		values[i] = f.float(a.raw(f, i))
+	}
+	return values, nil
+}
+
+// SetInt sets field name of item i
+func (a *Array) SetInt(name string, i int, v int64) error {
+	f, err := a.field(name)
+	if err != nil {
+		return err
+	}
+	f.put(a.raw(f, i), v, float64(v))
+	return nil
+}
+
+// SetFloat sets field name of item i
+func (a *Array) SetFloat(name string, i int, v float64) error {
+	f, err := a.field(name)
+	if err != nil {
+		return err
+	}
+	f.put(a.raw(f, i), int64(v), v)
+	return nil
+}
+
+// readFull reads len(b) bytes found at offset, reporting a short read as format.ErrTruncated
+func readFull(r io.Reader, b []byte, offset int64, detail string) error {
+	if _, err := io.ReadFull(r, b); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return format.NewError(format.ErrTruncated, offset, detail)
+		}
+		return err
+	}
+	return nil
+}
+
+// Read reads an array in the .npy format.
+// Truncated data is reported as format.ErrTruncated and invalid or unsupported headers as format.ErrBadHeader.
+func Read(r io.Reader) (*Array, error) {
+	br := bufio.NewReader(r)
+
+	preamble := make([]byte, 8)
+	if err := readFull(br, preamble, 0, "incomplete npy preamble"); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(preamble[:6], magic) {
+		return nil, format.NewError(format.ErrBadHeader, 0, "invalid npy magic string")
+	}
+
+	var headerLen int
+	switch preamble[6] {
+	case 1:
+		b := make([]byte, 2)
+		if err := readFull(br, b, 8, "incomplete npy header length"); err != nil {
+			return nil, err
+		}
+		headerLen = int(binary.LittleEndian.Uint16(b))
+	case 2, 3:
+		b := make([]byte, 4)
+		if err := readFull(br, b, 8, "incomplete npy header length"); err != nil {
+			return nil, err
+		}
+		headerLen = int(binary.LittleEndian.Uint32(b))
+	default:
+		return nil, format.NewError(format.ErrBadHeader, 6, fmt.Sprintf("unsupported npy version %d", preamble[6]))
+	}
+
+	offset := int64(10)
+	if preamble[6] != 1 {
+		offset = 12
+	}
+	header := &bytes.Buffer{}
+	if n, err := io.CopyN(header, br, int64(headerLen)); err != nil {
+		if err == io.EOF {
+			return nil, format.NewError(format.ErrTruncated, offset+n, "incomplete npy header")
+		}
+		return nil, err
+	}
+
+	a, err := parseHeader(header.String())
+	if err != nil {
+		return nil, format.NewError(format.ErrBadHeader, offset, err.Error())
+	}
+	offset += int64(headerLen)
+
+	// the buffer grows as data is read, so a header claiming a huge shape fails on the data size instead of
+	// allocating it upfront
+	size := int64(a.Len() * a.itemSize)
+	data := &bytes.Buffer{}
+	if n, err := io.CopyN(data, br, size); err != nil {
+		if err == io.EOF {
+			return nil, format.NewError(format.ErrTruncated, offset+n-n%int64(a.itemSize), "incomplete npy data")
+		}
+		return nil, err
+	}
+	a.data = data.Bytes()
+
+	return a, nil
+}
+
+func parseHeader(header string) (*Array, error) {
+	v, err := parseLiteral(header)
+	if err != nil {
+		return nil, err
+	}
+
+	dict, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("Invalid npy header")
+	}
+
+	a := &Array{}
+
+	if fortran, ok := dict["fortran_order"].(bool); ok {
+		a.FortranOrder = fortran
+	}
+
+	shape, ok := dict["shape"].([]interface{})
+	if !ok {
+		return nil, errors.New("Invalid npy shape")
+	}
+	for _, s := range shape {
+		n, ok := s.(int)
+		if !ok {
+			return nil, errors.New("Invalid npy shape")
+		}
+		a.Shape = append(a.Shape, n)
+	}
+
+	switch descr := dict["descr"].(type) {
+	case string:
+		a.Fields = []Field{{Descr: descr}}
+	case []interface{}:
+		for _, d := range descr {
+			t, ok := d.([]interface{})
+			if !ok || len(t) != 2 {
+				return nil, errors.New("Unsupported npy dtype")
+			}
+			name, ok1 := t[0].(string)
+			typ, ok2 := t[1].(string)
+			if !ok1 || !ok2 {
+				return nil, errors.New("Unsupported npy dtype")
+			}
+			a.Fields = append(a.Fields, Field{Name: name, Descr: typ})
+		}
+	default:
+		return nil, errors.New("Invalid npy dtype")
+	}
+
+	if err := a.layout(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *Array) header() string {
+	descr := ""
+	if len(a.Fields) == 1 && a.Fields[0].Name == "" {
+		descr = "'" + a.Fields[0].Descr + "'"
+	} else {
+		parts := make([]string, len(a.Fields))
+		for i, f := range a.Fields {
+			parts[i] = fmt.Sprintf("('%s', '%s')", f.Name, f.Descr)
+		}
+		descr = "[" + strings.Join(parts, ", ") + "]"
+	}
+
+	shape := make([]string, len(a.Shape))
+	for i, s := range a.Shape {
+		shape[i] = fmt.Sprint(s)
+	}
+	shapeStr := strings.Join(shape, ", ")
+	if len(a.Shape) == 1 {
+		shapeStr += ","
+	}
+
+	fortran := "False"
+	if a.FortranOrder {
+		fortran = "True"
+	}
+
+	return fmt.Sprintf("{'descr': %s, 'fortran_order': %s, 'shape': (%s), }", descr, fortran, shapeStr)
+}
+
+// Write writes the array in the .npy format
+func (a *Array) Write(w io.Writer) error {
+	header := a.header()
+
+	// The header is padded with spaces and terminated by a newline so that data is aligned to 64 bytes
+	version := byte(1)
+	prefix := 10
+	total := prefix + len(header) + 1
+	if total+63 >= 1<<16 {
+		version, prefix = 2, 12
+		total = prefix + len(header) + 1
+	}
+	header += strings.Repeat(" ", (64-total%64)%64) + "\n"
+
+	bw := bufio.NewWriter(w)
+	bw.Write(magic)
+	bw.Write([]byte{version, 0})
+	if version == 1 {
+		binary.Write(bw, binary.LittleEndian, uint16(len(header)))
+	} else {
+		binary.Write(bw, binary.LittleEndian, uint32(len(header)))
+	}
+	bw.WriteString(header)
+	bw.Write(a.data)
+
+	return bw.Flush()
+}
+
+// ReadFile reads an array from a .npy file
+func ReadFile(path string) (*Array, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return Read(f)
+}
+
+// ReadNpz reads every array of a .npz archive. Keys are the array names, without the .npy extension.
+func ReadNpz(path string) (map[string]*Array, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return ReadNpzFrom(f, info.Size())
+}
+
+// ReadNpzFrom reads every array of a .npz archive of size bytes from r, such as a bytes.Reader
+func ReadNpzFrom(r io.ReaderAt, size int64) (map[string]*Array, error) {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	arrays := map[string]*Array{}
+	for _, zf := range z.File {
+		r, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		a, err := Read(r)
+		r.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", zf.Name, err)
+		}
+		arrays[strings.TrimSuffix(zf.Name, ".npy")] = a
+	}
+
+	return arrays, nil
+}
+
+// WriteNpz writes arrays to a compressed .npz archive, as numpy.savez_compressed does
+func WriteNpz(path string, arrays map[string]*Array) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if err := WriteNpzTo(f, arrays); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// WriteNpzTo writes arrays to w as a compressed .npz archive. Arrays are sorted by name, so the same arrays always
+// produce the same archive.
+func WriteNpzTo(w io.Writer, arrays map[string]*Array) error {
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	z := zip.NewWriter(w)
+	for _, name := range names {
+		fw, err := z.CreateHeader(&zip.FileHeader{Name: name + ".npy", Method: zip.Deflate})
+		if err != nil {
+			return err
+		}
+		if err := arrays[name].Write(fw); err != nil {
+			return err
+		}
+	}
+
+	return z.Close()
+}
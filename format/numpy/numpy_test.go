@@ -0,0 +1,164 @@
+package numpy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision/format"
+)
+
+func npyBytes(header string, data []byte) []byte {
+	b := &bytes.Buffer{}
+	b.Write(magic)
+	b.Write([]byte{1, 0})
+	binary.Write(b, binary.LittleEndian, uint16(len(header)))
+	b.WriteString(header)
+	b.Write(data)
+	return b.Bytes()
+}
+
+func TestRead(t *testing.T) {
+	structured := []byte{
+		1, 0, 2, 0, 10, 0, 0, 0, 0, 0, 0, 0, 1,
+		3, 0, 4, 0, 20, 0, 0, 0, 0, 0, 0, 0, 0,
+	}
+
+	a, err := Read(bytes.NewReader(npyBytes(
+		"{'descr': [('x', '<u2'), ('y', '<u2'), ('t', '<i8'), ('p', '?')], 'fortran_order': False, 'shape': (2,), }\n",
+		structured,
+	)))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(a.Shape, []int{2}) || len(a.Fields) != 4 {
+		t.Fatalf("Read() shape = %v, fields = %v", a.Shape, a.Fields)
+	}
+
+	x, _ := a.Ints("x")
+	ts, _ := a.Ints("t")
+	p, _ := a.Ints("p")
+	if !reflect.DeepEqual(x, []int64{1, 3}) || !reflect.DeepEqual(ts, []int64{10, 20}) || !reflect.DeepEqual(p, []int64{1, 0}) {
+		t.Errorf("Read() x = %v, t = %v, p = %v", x, ts, p)
+	}
+
+	simple := []byte{0, 0, 0, 0, 0, 0, 0xf8, 0x3f, 0, 0, 0, 0, 0, 0, 0, 0xc0}
+	a, err = Read(bytes.NewReader(npyBytes("{'descr': '<f8', 'fortran_order': False, 'shape': (1, 2), }\n", simple)))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if f, _ := a.Floats(""); !reflect.DeepEqual(f, []float64{1.5, -2}) {
+		t.Errorf("Read() floats = %v", f)
+	}
+
+	invalid := [][]byte{
+		[]byte("not a numpy file"),
+		npyBytes("{'descr': '<c16', 'fortran_order': False, 'shape': (1,), }\n", make([]byte, 16)),
+		npyBytes("{'descr': '<f8', 'fortran_order': False, 'shape': (2,), }\n", make([]byte, 8)),
+		npyBytes("{'descr': '<f8', 'shape': (2,)\n", make([]byte, 16)),
+		npyBytes("{'descr': '<f8', 'fortran_order': False, 'shape': (-1,), }\n", make([]byte, 8)),
+		npyBytes("{'descr': '<f8', 'fortran_order': False, 'shape': (1099511627776, 1099511627776), }\n", nil),
+		npyBytes("{'descr': '<f8', 'fortran_order': False, 'shape': (1000000000000,), }\n", make([]byte, 8)),
+	}
+	for _, b := range invalid {
+		if _, err := Read(bytes.NewReader(b)); err == nil {
+			t.Errorf("Read() should return an error for %q", b)
+		}
+	}
+
+	var fErr *format.Error
+	if _, err := Read(bytes.NewReader(invalid[2])); !errors.Is(err, format.ErrTruncated) || !errors.As(err, &fErr) || fErr.Offset != int64(len(invalid[2])) {
+		t.Errorf("Read() error = %v, want %v at offset %v", err, format.ErrTruncated, len(invalid[2]))
+	}
+	if _, err := Read(bytes.NewReader(invalid[4])); !errors.Is(err, format.ErrBadHeader) {
+		t.Errorf("Read() error = %v, want %v", err, format.ErrBadHeader)
+	}
+}
+
+func TestNewArray(t *testing.T) {
+	fields := []Field{{Descr: "<f8"}}
+	for _, shape := range [][]int{{-1}, {-2, -3}, {1 << 40, 1 << 40}} {
+		if _, err := NewArray(fields, shape...); err == nil {
+			t.Errorf("NewArray() should return an error for shape %v", shape)
+		}
+	}
+	if a, err := NewArray(fields, 0, 3); err != nil || a.Len() != 0 {
+		t.Errorf("NewArray() = %v, %v for an empty shape", a, err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	a, err := NewArray([]Field{{Name: "ts", Descr: "<u8"}, {Name: "w", Descr: "<f4"}, {Name: "class_id", Descr: "|u1"}}, 3)
+	if err != nil {
+		t.Fatalf("NewArray() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		a.SetInt("ts", i, int64(i*1000))
+		a.SetFloat("w", i, float64(i)+0.5)
+		a.SetInt("class_id", i, int64(i))
+	}
+
+	b := &bytes.Buffer{}
+	if err := a.Write(b); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if (b.Len()-3*13)%64 != 0 {
+		t.Errorf("Write() header should be aligned to 64 bytes")
+	}
+
+	got, err := Read(b)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	w, _ := got.Floats("w")
+	ts, _ := got.Ints("ts")
+	if !reflect.DeepEqual(w, []float64{0.5, 1.5, 2.5}) || !reflect.DeepEqual(ts, []int64{0, 1000, 2000}) {
+		t.Errorf("Read() after Write() w = %v, ts = %v", w, ts)
+	}
+
+	if _, err := got.Ints("missing"); err == nil {
+		t.Errorf("Ints() with an unknown field should return an error")
+	}
+}
+
+func TestWriteNpzTo(t *testing.T) {
+	a, _ := NewArray([]Field{{Name: "x", Descr: "<u2"}}, 2)
+	a.SetInt("x", 0, 3)
+	a.SetInt("x", 1, 7)
+
+	var buf bytes.Buffer
+	if err := WriteNpzTo(&buf, map[string]*Array{"events": a}); err != nil {
+		t.Fatalf("WriteNpzTo() error = %v", err)
+	}
+
+	arrays, err := ReadNpzFrom(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ReadNpzFrom() error = %v", err)
+	}
+	x, _ := arrays["events"].Ints("x")
+	if !reflect.DeepEqual(x, []int64{3, 7}) {
+		t.Errorf("ReadNpzFrom() x = %v, want [3 7]", x)
+	}
+
+	many := map[string]*Array{"events": a, "boxes": a, "labels": a, "times": a}
+	var first, second bytes.Buffer
+	WriteNpzTo(&first, many)
+	WriteNpzTo(&second, many)
+	z, err := zip.NewReader(bytes.NewReader(first.Bytes()), int64(first.Len()))
+	if err != nil {
+		t.Fatalf("WriteNpzTo() wrote an invalid archive: %v", err)
+	}
+	names := []string{}
+	for _, f := range z.File {
+		names = append(names, f.Name)
+	}
+	if !bytes.Equal(first.Bytes(), second.Bytes()) || !reflect.DeepEqual(names, []string{"boxes.npy", "events.npy", "labels.npy", "times.npy"}) {
+		t.Errorf("WriteNpzTo() entries = %v, want a deterministic archive sorted by name", names)
+	}
+}
@@ -1,18 +1,26 @@
 package prophesee
 
 import (
+	"bufio"
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 
 	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/format"
 )
 
+// eventSize is the size of a CD event, in bytes
+const eventSize = 8
+
 // Dat implements Prophesee RAW DAT format reading and writing
 // More information can be found in the official documentation
 // https://docs.prophesee.ai/stable/data_formats/file_formats/dat.html
 type Dat struct {
 	FilePath string
+	Mode     format.Mode
 }
 
 func (d Dat) newEventFromBytes(data []byte) event.Event {
@@ -37,59 +45,92 @@ func (d Dat) ReadEvents() (event.EventCapture, error) {
 
 	defer f.Close()
 
+	return d.Decode(f)
+}
+
+// Decode reads events in the Prophesee RAW DAT format from r.
+// In Strict mode, a truncated last event is reported as format.ErrTruncated.
+func (d Dat) Decode(r io.Reader) (event.EventCapture, error) {
+	br := bufio.NewReader(r)
 	ev := []event.Event{}
-	bc := 2
 
 	mX, mY := 0, 0
-	evSize := 0
 
-	evSize, err = d.seekFirstEvent(f)
-	bc = evSize
-
-	bb := make([]byte, 8)
-	for bc == evSize {
-		bc, err = f.Read(bb)
-		if bc == evSize && err == nil {
+	evSize, offset, err := d.seekFirstEvent(br)
+	if err != nil {
+		return event.EventCapture{}, err
+	}
 
-			n := d.newEventFromBytes(bb)
-			if n.Coords.X > mX {
-				mX = n.Coords.X
-			}
-			if n.Coords.Y > mY {
-				mY = n.Coords.Y
+	bb := make([]byte, evSize)
+	for ; ; offset += int64(evSize) {
+		bc, err := io.ReadFull(br, bb)
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
+			if d.Mode == format.Lenient {
+				break
 			}
-			ev = append(ev, n)
+			return event.EventCapture{}, format.NewError(format.ErrTruncated, offset, fmt.Sprintf("%d trailing bytes", bc))
+		}
+		if err != nil {
+			return event.EventCapture{}, err
 		}
+
+		n := d.newEventFromBytes(bb)
+		if n.Coords.X > mX {
+			mX = n.Coords.X
+		}
+		if n.Coords.Y > mY {
+			mY = n.Coords.Y
+		}
+		ev = append(ev, n)
 	}
 
 	return event.EventCapture{Events: ev, Width: mX + 1, Height: mY + 1}, nil
 }
 
-func (d Dat) seekFirstEvent(f *os.File) (int, error) {
-	var err error
+// seekFirstEvent consumes the ASCII header lines, which start with "% ", and the binary event type and size.
+// It returns the event size and the offset of the first event.
+func (d Dat) seekFirstEvent(r io.Reader) (int, int64, error) {
 	bh := make([]byte, 2)
-	bc := 2
+	bhi := make([]byte, 1)
+	offset := int64(0)
 
-	evSize := 0
+	for {
+		if _, err := io.ReadFull(r, bh); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return 0, offset, format.NewError(format.ErrBadHeader, offset, "missing event type and size")
+			}
+			return 0, offset, err
+		}
+		offset += 2
 
-	for bc == 2 {
-		bc, err = f.Read(bh)
 		if bh[0] == '%' && bh[1] == ' ' {
-			bhi := make([]byte, 1)
-			for bhi[0] != '\n' {
-				_, err = f.Read(bhi)
+			for bhi[0] = 0; bhi[0] != '\n'; offset++ {
+				if _, err := io.ReadFull(r, bhi); err != nil {
+					if err == io.EOF {
+						return 0, offset, format.NewError(format.ErrBadHeader, offset, "header line without newline")
+					}
+					return 0, offset, err
+				}
 			}
 			continue
 		}
 
-		evSize = int(bh[1])
-		break
+		if bh[1] != eventSize {
+			return 0, offset - 1, format.NewError(format.ErrBadHeader, offset-1, fmt.Sprintf("event size %d is not %d", bh[1], eventSize))
+		}
+		return eventSize, offset, nil
 	}
-
-	return evSize, err
 }
 
 // WriteEvents will write events to file in the Prophesee RAW DAT format
 func (d Dat) WriteEvents(evCap event.EventCapture) error {
 	return errors.New("Prophesee.WriteEvents is not implemented")
 }
+
+// Encode will write events to w in the Prophesee RAW DAT format
+func (d Dat) Encode(w io.Writer, evCap event.EventCapture) error {
+	return errors.New("Prophesee.Encode is not implemented")
+}
@@ -0,0 +1,94 @@
+package prophesee
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/format"
+)
+
+var _ format.Decoder = Dat{}
+
+func TestDat_ReadEvents(t *testing.T) {
+	header := "% Data file containing CD events.\n% Version 2\n"
+	// ts 100, x 5, y 3, p 1
+	row := []byte{100, 0, 0, 0, 5, 0xC0, 0, 0x10}
+
+	tests := []struct {
+		name   string
+		data   []byte
+		mode   format.Mode
+		want   []event.Event
+		err    error
+		offset int64
+	}{
+		{
+			name: "Test events",
+			data: append(append([]byte(header), 0, 8), append(row, row...)...),
+			want: []event.Event{{Coords: event.Point2D{X: 5, Y: 3}, Ts: 100, P: 1}, {Coords: event.Point2D{X: 5, Y: 3}, Ts: 100, P: 1}},
+		},
+		{
+			name:   "Test header without newline",
+			data:   []byte("% Data file"),
+			err:    format.ErrBadHeader,
+			offset: int64(len("% Data file")),
+		},
+		{
+			name:   "Test missing event size",
+			data:   []byte(header),
+			err:    format.ErrBadHeader,
+			offset: int64(len(header)),
+		},
+		{
+			name:   "Test invalid event size",
+			data:   append([]byte(header), 0, 4),
+			err:    format.ErrBadHeader,
+			offset: int64(len(header)) + 1,
+		},
+		{
+			name:   "Test truncated event",
+			data:   append(append([]byte(header), 0, 8), append(row, row[:3]...)...),
+			err:    format.ErrTruncated,
+			offset: int64(len(header)) + 10,
+		},
+		{
+			name: "Test truncated event in lenient mode",
+			data: append(append([]byte(header), 0, 8), append(row, row[:3]...)...),
+			mode: format.Lenient,
+			want: []event.Event{{Coords: event.Point2D{X: 5, Y: 3}, Ts: 100, P: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sample_td.dat")
+			if err := os.WriteFile(path, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			evCap, err := Dat{FilePath: path, Mode: tt.mode}.ReadEvents()
+			if tt.err != nil {
+				var fErr *format.Error
+				if !errors.Is(err, tt.err) || !errors.As(err, &fErr) || fErr.Offset != tt.offset {
+					t.Errorf("Dat.ReadEvents() error = %v, want %v at offset %v", err, tt.err, tt.offset)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Dat.ReadEvents() error = %v", err)
+			}
+			if !reflect.DeepEqual(evCap.Events, tt.want) {
+				t.Errorf("Dat.ReadEvents() = %v, want %v", evCap.Events, tt.want)
+			}
+
+			evCap, err = Dat{Mode: tt.mode}.Decode(bytes.NewReader(tt.data))
+			if err != nil || !reflect.DeepEqual(evCap.Events, tt.want) {
+				t.Errorf("Dat.Decode() = %v, %v, want %v", evCap.Events, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package motion
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+// Warper moves an event to its position at a reference timestamp under a candidate motion
+type Warper interface {
+	Warp(e event.Event, tRef int) (x, y float64)
+}
+
+// Flow is a constant optical flow, in pixels per second
+type Flow struct {
+	VX, VY float64
+}
+
+// Warp moves e along the flow to tRef
+func (f Flow) Warp(e event.Event, tRef int) (x, y float64) {
+	dt := float64(e.Ts-tRef) / 1e6
+	return float64(e.Coords.X) - f.VX*dt, float64(e.Coords.Y) - f.VY*dt
+}
+
+// Rotation is a constant in-plane rotation around a center, in radians per second
+type Rotation struct {
+	Omega  float64
+	CX, CY float64
+}
+
+// Warp rotates e back to its position at tRef
+func (r Rotation) Warp(e event.Event, tRef int) (x, y float64) {
+	angle := -r.Omega * float64(e.Ts-tRef) / 1e6
+	sin, cos := math.Sincos(angle)
+	dx, dy := float64(e.Coords.X)-r.CX, float64(e.Coords.Y)-r.CY
+	return r.CX + dx*cos - dy*sin, r.CY + dx*sin + dy*cos
+}
+
+// IWE builds the image of warped events, a [height][width] matrix where each warped event votes for its four
+// nearest pixels with bilinear weights
+func IWE(src []event.Event, w Warper, tRef, width, height int) ([][]float64, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("Invalid matrix size")
+	}
+
+	m := make([][]float64, height)
+	for i := range m {
+		m[i] = make([]float64, width)
+	}
+
+	for _, e := range src {
+		x, y := w.Warp(e, tRef)
+		x0, y0 := math.Floor(x), math.Floor(y)
+		fx, fy := x-x0, y-y0
+
+		for _, c := range []struct {
+			dx, dy int
+			w      float64
+		}{
+			{0, 0, (1 - fx) * (1 - fy)},
+			{1, 0, fx * (1 - fy)},
+			{0, 1, (1 - fx) * fy},
+			{1, 1, fx * fy},
+		} {
+			px, py := int(x0)+c.dx, int(y0)+c.dy
+			if px >= 0 && px < width && py >= 0 && py < height {
+				m[py][px] += c.w
+			}
+		}
+	}
+	return m, nil
+}
+
+// Blur smooths an IWE with a Gaussian kernel of standard deviation sigma, in pixels.
+// Smoothing removes the bias of bilinear voting toward motions aligning events with pixel centers.
+func Blur(iwe [][]float64, sigma float64) [][]float64 {
+	radius := int(math.Ceil(3 * sigma))
+	kernel := make([]float64, 2*radius+1)
+	sum := 0.0
+	for i := range kernel {
+		d := float64(i - radius)
+		kernel[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+
+	convolve := func(dst, src [][]float64, horizontal bool) {
+		for y, row := range src {
+			for x := range row {
+				v := 0.0
+				for k, w := range kernel {
+					sx, sy := x, y
+					if horizontal {
+						sx += k - radius
+					} else {
+						sy += k - radius
+					}
+					if sy >= 0 && sy < len(src) && sx >= 0 && sx < len(row) {
+						v += w * src[sy][sx]
+					}
+				}
+				dst[y][x] = v
+			}
+		}
+	}
+
+	tmp := make([][]float64, len(iwe))
+	dst := make([][]float64, len(iwe))
+	for i, row := range iwe {
+		tmp[i] = make([]float64, len(row))
+		dst[i] = make([]float64, len(row))
+	}
+	convolve(tmp, iwe, true)
+	convolve(dst, tmp, false)
+	return dst
+}
+
+// Focus measures the sharpness of an IWE. Higher values are sharper.
+type Focus func(iwe [][]float64) float64
+
+// Variance is the variance of the IWE, the focus loss of the original contrast maximization framework
+func Variance(iwe [][]float64) float64 {
+	sum, sumSq, n := 0.0, 0.0, 0
+	for _, row := range iwe {
+		for _, v := range row {
+			sum += v
+			sumSq += v * v
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+
+	mean := sum / float64(n)
+	return sumSq/float64(n) - mean*mean
+}
+
+// MeanSquare is the mean of the squared IWE values
+func MeanSquare(iwe [][]float64) float64 {
+	sumSq, n := 0.0, 0
+	for _, row := range iwe {
+		for _, v := range row {
+			sumSq += v * v
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sumSq / float64(n)
+}
+
+// Gradient is the mean squared magnitude of the IWE gradient, computed with forward differences
+func Gradient(iwe [][]float64) float64 {
+	sum, n := 0.0, 0
+	for y, row := range iwe {
+		for x, v := range row {
+			gx, gy := 0.0, 0.0
+			if x+1 < len(row) {
+				gx = row[x+1] - v
+			}
+			if y+1 < len(iwe) {
+				gy = iwe[y+1][x] - v
+			}
+			sum += gx*gx + gy*gy
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// ContrastMaximizer estimates motion by searching the parameters whose IWE has the highest focus.
+// The search evaluates a grid over the parameter range, then refines it around the best candidate.
+type ContrastMaximizer struct {
+	Width       int
+	Height      int
+	Focus       Focus   // focus loss, Variance when nil
+	Sigma       float64 // standard deviation of the IWE smoothing, 1 when zero. Negative values disable smoothing
+	MaxFlow     float64 // flow search range, in pixels per second, for both axes
+	MaxOmega    float64 // rotation search range, in radians per second
+	Steps       int     // grid points per parameter, 11 when zero
+	Refinements int     // number of refinements of the grid, 3 when zero
+}
+
+// WindowEstimate is the flow estimated for a time window
+type WindowEstimate struct {
+	Start int // window start timestamp, inclusive
+	End   int // window end timestamp, exclusive
+	Flow  Flow
+	Score float64 // focus of the IWE with the estimated flow
+}
+
+// score computes the focus of the smoothed IWE of src warped by w
+func (c ContrastMaximizer) score(src []event.Event, w Warper, tRef int) (float64, error) {
+	iwe, err := IWE(src, w, tRef, c.Width, c.Height)
+	if err != nil {
+		return 0, err
+	}
+
+	sigma := c.Sigma
+	if sigma == 0 {
+		sigma = 1
+	}
+	if sigma > 0 {
+		iwe = Blur(iwe, sigma)
+	}
+
+	if c.Focus == nil {
+		return Variance(iwe), nil
+	}
+	return c.Focus(iwe), nil
+}
+
+// search maximizes f over the box [lo, hi] with a refined grid search
+func (c ContrastMaximizer) search(f func(p []float64) (float64, error), lo, hi []float64) ([]float64, float64, error) {
+	steps, refinements := c.Steps, c.Refinements
+	if steps <= 1 {
+		steps = 11
+	}
+	if refinements <= 0 {
+		refinements = 3
+	}
+
+	lo = append([]float64{}, lo...)
+	hi = append([]float64{}, hi...)
+	best := make([]float64, len(lo))
+	bestScore := math.Inf(-1)
+
+	for r := 0; r <= refinements; r++ {
+		idx := make([]int, len(lo))
+		p := make([]float64, len(lo))
+		for {
+			for d := range p {
+				p[d] = lo[d] + (hi[d]-lo[d])*float64(idx[d])/float64(steps-1)
+			}
+			score, err := f(p)
+			if err != nil {
+				return nil, 0, err
+			}
+			if score > bestScore {
+				bestScore = score
+				copy(best, p)
+			}
+
+			d := 0
+			for ; d < len(idx); d++ {
+				idx[d]++
+				if idx[d] < steps {
+					break
+				}
+				idx[d] = 0
+			}
+			if d == len(idx) {
+				break
+			}
+		}
+
+		// the next grid spans one cell of the current grid around the best candidate
+		for d := range lo {
+			cell := (hi[d] - lo[d]) / float64(steps-1)
+			lo[d], hi[d] = best[d]-cell, best[d]+cell
+		}
+	}
+
+	return best, bestScore, nil
+}
+
+// EstimateFlow estimates the flow of src, warping events to the timestamp of the first event
+func (c ContrastMaximizer) EstimateFlow(src []event.Event) (Flow, float64, error) {
+	if len(src) == 0 {
+		return Flow{}, 0, nil
+	}
+
+	tRef := src[0].Ts
+	p, score, err := c.search(func(p []float64) (float64, error) {
+		return c.score(src, Flow{VX: p[0], VY: p[1]}, tRef)
+	}, []float64{-c.MaxFlow, -c.MaxFlow}, []float64{c.MaxFlow, c.MaxFlow})
+	if err != nil {
+		return Flow{}, 0, err
+	}
+
+	return Flow{VX: p[0], VY: p[1]}, score, nil
+}
+
+// EstimateRotation estimates the rotation of src around the sensor center, warping events to the timestamp
+// of the first event
+func (c ContrastMaximizer) EstimateRotation(src []event.Event) (Rotation, float64, error) {
+	cx, cy := float64(c.Width-1)/2, float64(c.Height-1)/2
+	if len(src) == 0 {
+		return Rotation{CX: cx, CY: cy}, 0, nil
+	}
+
+	tRef := src[0].Ts
+	p, score, err := c.search(func(p []float64) (float64, error) {
+		return c.score(src, Rotation{Omega: p[0], CX: cx, CY: cy}, tRef)
+	}, []float64{-c.MaxOmega}, []float64{c.MaxOmega})
+	if err != nil {
+		return Rotation{}, 0, err
+	}
+
+	return Rotation{Omega: p[0], CX: cx, CY: cy}, score, nil
+}
+
+// EstimateFlowWindows estimates the flow of consecutive windows of windowUs microsseconds, starting at the
+// first event. Events are expected to be sorted by timestamp.
+func (c ContrastMaximizer) EstimateFlowWindows(src []event.Event, windowUs int) ([]WindowEstimate, error) {
+	if windowUs <= 0 {
+		return nil, errors.New("Invalid window size")
+	}
+
+	estimates := []WindowEstimate{}
+	if len(src) == 0 {
+		return estimates, nil
+	}
+
+	first := 0
+	for start := src[0].Ts; first < len(src); start += windowUs {
+		last := first
+		for last < len(src) && src[last].Ts < start+windowUs {
+			last++
+		}
+
+		flow, score, err := c.EstimateFlow(src[first:last])
+		if err != nil {
+			return nil, err
+		}
+		estimates = append(estimates, WindowEstimate{Start: start, End: start + windowUs, Flow: flow, Score: score})
+		first = last
+	}
+
+	return estimates, nil
+}
+
+// FlowModel integrates window estimates into a PiecewiseLinear model, with no displacement at the start of the
+// first window, so a Compensator can stabilize the events the estimates were computed on
+func FlowModel(estimates []WindowEstimate) (PiecewiseLinear, error) {
+	if len(estimates) == 0 {
+		return PiecewiseLinear{}, nil
+	}
+
+	knots := []Knot{{Ts: estimates[0].Start}}
+	x, y := 0.0, 0.0
+	for _, e := range estimates {
+		if e.Start != knots[len(knots)-1].Ts {
+			return PiecewiseLinear{}, errors.New("Estimates must be consecutive windows")
+		}
+		dt := float64(e.End-e.Start) / 1e6
+		x += e.Flow.VX * dt
+		y += e.Flow.VY * dt
+		knots = append(knots, Knot{Ts: e.End, X: x, Y: y})
+	}
+
+	return NewPiecewiseLinear(knots...)
+}
+
+// WarpEvents warps src to tRef, rounding to the nearest pixel and discarding events outside of the sensor.
+// The warped events can be used to build sharp SAE or renderings.
+func WarpEvents(src []event.Event, w Warper, tRef, width, height int) []event.Event {
+	dst := make([]event.Event, 0, len(src))
+
+	for _, e := range src {
+		x, y := w.Warp(e, tRef)
+		e.Coords = event.Point2D{X: Nearest.Apply(x), Y: Nearest.Apply(y)}
+		if e.Coords.X >= 0 && e.Coords.X < width && e.Coords.Y >= 0 && e.Coords.Y < height {
+			dst = append(dst, e)
+		}
+	}
+
+	return dst
+}
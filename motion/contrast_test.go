@@ -0,0 +1,111 @@
+package motion
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+// movingDots creates events of random dots whose positions at each timestamp are given by position
+func movingDots(n int, position func(x, y float64, ts int) (float64, float64)) []event.Event {
+	r := rand.New(rand.NewSource(1))
+	dots := make([][2]float64, n)
+	for i := range dots {
+		dots[i] = [2]float64{16 + r.Float64()*32, 16 + r.Float64()*32}
+	}
+
+	ev := []event.Event{}
+	for ts := 0; ts < 50e3; ts += 500 {
+		for _, d := range dots {
+			x, y := position(d[0], d[1], ts)
+			ev = append(ev, event.Event{Coords: event.Point2D{X: int(math.Round(x)), Y: int(math.Round(y))}, Ts: ts, P: 1})
+		}
+	}
+	return ev
+}
+
+func TestContrastMaximizer_EstimateFlow(t *testing.T) {
+	src := movingDots(20, func(x, y float64, ts int) (float64, float64) {
+		return x + 200*float64(ts)/1e6, y - 100*float64(ts)/1e6
+	})
+
+	c := ContrastMaximizer{Width: 64, Height: 64, MaxFlow: 400}
+	for _, focus := range []Focus{Variance, MeanSquare, Gradient} {
+		c.Focus = focus
+		flow, _, err := c.EstimateFlow(src)
+		if err != nil {
+			t.Fatalf("EstimateFlow() error = %v", err)
+		}
+		if math.Abs(flow.VX-200) > 20 || math.Abs(flow.VY+100) > 20 {
+			t.Errorf("EstimateFlow() = %v, want about {200 -100}", flow)
+		}
+	}
+
+	sharp, err := IWE(WarpEvents(src, Flow{VX: 200, VY: -100}, 0, 64, 64), Flow{}, 0, 64, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blurred, _ := IWE(src, Flow{}, 0, 64, 64)
+	if Variance(sharp) <= Variance(blurred) {
+		t.Errorf("WarpEvents() should increase the contrast of the IWE")
+	}
+
+	if _, _, err := (ContrastMaximizer{}).EstimateFlow(src); err == nil {
+		t.Errorf("EstimateFlow() without sensor size should return an error")
+	}
+}
+
+func TestContrastMaximizer_EstimateRotation(t *testing.T) {
+	cx, cy := 31.5, 31.5
+	src := movingDots(20, func(x, y float64, ts int) (float64, float64) {
+		return Rotation{Omega: -6, CX: cx, CY: cy}.Warp(event.Event{Coords: event.Point2D{X: int(x), Y: int(y)}, Ts: ts}, 0)
+	})
+
+	rotation, _, err := ContrastMaximizer{Width: 64, Height: 64, MaxOmega: 10}.EstimateRotation(src)
+	if err != nil {
+		t.Fatalf("EstimateRotation() error = %v", err)
+	}
+	if math.Abs(rotation.Omega-6) > 0.3 || rotation.CX != cx || rotation.CY != cy {
+		t.Errorf("EstimateRotation() = %v, want about {6 %v %v}", rotation, cx, cy)
+	}
+}
+
+func TestFlowModel(t *testing.T) {
+	src := movingDots(20, func(x, y float64, ts int) (float64, float64) {
+		if ts < 25e3 {
+			return x + 200*float64(ts)/1e6, y
+		}
+		return x + 5, y + 200*float64(ts-25e3)/1e6
+	})
+
+	estimates, err := ContrastMaximizer{Width: 64, Height: 64, MaxFlow: 400}.EstimateFlowWindows(src, 25e3)
+	if err != nil || len(estimates) != 2 {
+		t.Fatalf("EstimateFlowWindows() = %v, error = %v", estimates, err)
+	}
+
+	model, err := FlowModel(estimates)
+	if err != nil {
+		t.Fatalf("FlowModel() error = %v", err)
+	}
+
+	dx, dy := model.Displacement(50e3)
+	if math.Abs(dx-5) > 1 || math.Abs(dy-5) > 1 {
+		t.Errorf("FlowModel() displacement = %v, %v, want about 5, 5", dx, dy)
+	}
+
+	exact, err := FlowModel([]WindowEstimate{
+		{Start: 0, End: 100e3, Flow: Flow{VX: 100}},
+		{Start: 100e3, End: 200e3, Flow: Flow{VY: -100}},
+	})
+	want := []Knot{{Ts: 0}, {Ts: 100e3, X: 10}, {Ts: 200e3, X: 10, Y: -10}}
+	if err != nil || !reflect.DeepEqual(exact.Knots(), want) {
+		t.Errorf("FlowModel() = %v, error = %v, want %v", exact.Knots(), err, want)
+	}
+
+	if _, err := FlowModel([]WindowEstimate{{Start: 0, End: 10}, {Start: 20, End: 30}}); err == nil {
+		t.Errorf("FlowModel() with a gap should return an error")
+	}
+}
@@ -0,0 +1,155 @@
+package motion
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+func TestPiecewiseLinear_Displacement(t *testing.T) {
+	p, _ := NewPiecewiseLinear(
+		Knot{Ts: 0, X: 0, Y: 0},
+		Knot{Ts: 105e3, X: 3.5, Y: 7},
+		Knot{Ts: 210e3, X: 7, Y: 0},
+		Knot{Ts: 315e3, X: 0, Y: 0},
+	)
+
+	tests := []struct {
+		name   string
+		ts     int
+		wantDx float64
+		wantDy float64
+	}{
+		{name: "Test before first knot", ts: -10, wantDx: 0, wantDy: 0},
+		{name: "Test first segment", ts: 52500, wantDx: 1.75, wantDy: 3.5},
+		{name: "Test knot", ts: 105e3, wantDx: 3.5, wantDy: 7},
+		{name: "Test second segment", ts: 157500, wantDx: 5.25, wantDy: 3.5},
+		{name: "Test third segment", ts: 262500, wantDx: 3.5, wantDy: 0},
+		{name: "Test after last knot", ts: 400e3, wantDx: 0, wantDy: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dx, dy := p.Displacement(tt.ts)
+			if dx != tt.wantDx || dy != tt.wantDy {
+				t.Errorf("PiecewiseLinear.Displacement() = %v, %v, want %v, %v", dx, dy, tt.wantDx, tt.wantDy)
+			}
+		})
+	}
+
+	if dx, dy := (PiecewiseLinear{}).Displacement(10); dx != 0 || dy != 0 {
+		t.Errorf("PiecewiseLinear.Displacement() without knots = %v, %v", dx, dy)
+	}
+}
+
+func TestNMNISTSaccades(t *testing.T) {
+	tests := []struct {
+		name   string
+		ts     int
+		wantDx float64
+		wantDy float64
+	}{
+		{name: "Test start", ts: 0, wantDx: 0, wantDy: 0},
+		{name: "Test first saccade", ts: 52500, wantDx: 1.75, wantDy: 1.75},
+		{name: "Test end of first saccade", ts: 105e3, wantDx: 3.5, wantDy: 3.5},
+		{name: "Test second saccade", ts: 157500, wantDx: 5.25, wantDy: 10.5},
+		{name: "Test end of second saccade", ts: 210e3, wantDx: 7, wantDy: 14},
+		{name: "Test third saccade", ts: 262500, wantDx: 10.5, wantDy: 0},
+		{name: "Test end of third saccade", ts: 315e3, wantDx: 14, wantDy: 0},
+		{name: "Test after the saccades", ts: 400e3, wantDx: 14, wantDy: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dx, dy := NMNISTSaccades.Displacement(tt.ts)
+			if math.Abs(dx-tt.wantDx) > 1e-9 || math.Abs(dy-tt.wantDy) > 1e-9 {
+				t.Errorf("NMNISTSaccades.Displacement() = %v, %v, want %v, %v", dx, dy, tt.wantDx, tt.wantDy)
+			}
+		})
+	}
+}
+
+func TestNewPiecewiseLinear(t *testing.T) {
+	if _, err := NewPiecewiseLinear(Knot{Ts: 10}, Knot{Ts: 10, X: 1}); err == nil {
+		t.Errorf("NewPiecewiseLinear() with repeated timestamps should return an error")
+	}
+
+	knots := []Knot{{Ts: 0}, {Ts: 50e3, X: 2, Y: -2}}
+	p, err := NewPiecewiseLinear(knots...)
+	if err != nil || !reflect.DeepEqual(p.Knots(), knots) {
+		t.Errorf("NewPiecewiseLinear() = %v, error = %v", p.Knots(), err)
+	}
+}
+
+func TestRounding_Apply(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Rounding
+		want []int
+	}{
+		{name: "Test nearest", r: Nearest, want: []int{2, -2, 2}},
+		{name: "Test truncate", r: Truncate, want: []int{1, -1, 2}},
+		{name: "Test floor", r: Floor, want: []int{1, -2, 2}},
+		{name: "Test ceil", r: Ceil, want: []int{2, -1, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []int{}
+			for _, v := range []float64{1.5, -1.5, 2.25} {
+				got = append(got, tt.r.Apply(v))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rounding.Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompensator_Apply(t *testing.T) {
+	model := Func(func(ts int) (float64, float64) { return float64(ts) / 10, -float64(ts) / 10 })
+	src := []event.Event{
+		{Coords: event.Point2D{X: 5, Y: 5}, Ts: 15, P: 1},
+		{Coords: event.Point2D{X: 0, Y: 8}, Ts: 30, P: 0},
+	}
+
+	tests := []struct {
+		name string
+		c    Compensator
+		want []event.Event
+	}{
+		{
+			name: "Test no clipping",
+			c:    Compensator{Model: model},
+			want: []event.Event{
+				{Coords: event.Point2D{X: 4, Y: 7}, Ts: 15, P: 1},
+				{Coords: event.Point2D{X: -3, Y: 11}, Ts: 30, P: 0},
+			},
+		},
+		{
+			name: "Test discard",
+			c:    Compensator{Model: model, Rounding: Floor, Clipping: Discard, Width: 10, Height: 10},
+			want: []event.Event{
+				{Coords: event.Point2D{X: 3, Y: 6}, Ts: 15, P: 1},
+			},
+		},
+		{
+			name: "Test clamp",
+			c:    Compensator{Model: model, Clipping: Clamp, Width: 10, Height: 10},
+			want: []event.Event{
+				{Coords: event.Point2D{X: 4, Y: 7}, Ts: 15, P: 1},
+				{Coords: event.Point2D{X: 0, Y: 9}, Ts: 30, P: 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.Apply(src); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compensator.Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := Compensate([]event.Event{}, NMNISTSaccades); len(got) != 0 {
+		t.Errorf("Compensate() on empty input = %v", got)
+	}
+}
@@ -8,6 +8,28 @@ import (
 	"github.com/ffardo/go-event-vision"
 )
 
+// Generator produces noise from a single source of randomness. Every random decision, including
+// coordinates and polarities, is drawn from the same *rand.Rand, so a Generator created with a
+// given seed will always produce the same noise for the same input.
+// A Generator is not safe for concurrent use.
+type Generator struct {
+	random *rand.Rand
+}
+
+// NewGenerator creates a Generator seeded with seed
+func NewGenerator(seed int64) *Generator {
+	return NewGeneratorFromRand(rand.New(rand.NewSource(seed)))
+}
+
+func newTimeSeededGenerator() *Generator {
+	return NewGenerator(time.Now().UnixNano())
+}
+
+// NewGeneratorFromRand creates a Generator that draws all random decisions from random
+func NewGeneratorFromRand(random *rand.Rand) *Generator {
+	return &Generator{random: random}
+}
+
 type randomEventGenerator struct {
 	random *rand.Rand
 	factor float64
@@ -20,7 +42,7 @@ func (r randomEventGenerator) generateRandomEvent(ts int) event.Event {
 	y := r.random.Intn(r.maxY)
 
 	pol := 0
-	if rand.Intn(100) > 50 {
+	if r.random.Intn(100) > 50 {
 		pol = 1
 	}
 
@@ -51,51 +73,85 @@ func (r randomEventGenerator) flipCoin() bool {
 	return r.random.Float64() > 1.0-r.factor
 }
 
-// ApplyAdditive inserts additive noise events into event stream.  Since new events are added to the stream, applying additive noise will
-// result in a larger event slice.
-// The factor argument will determine the likelihood of a random event to be added to the stream for each existing event.
-// If factor is set to 0.0, no new events will be added.
-// If factor is set to 0.25 there is a 25% chance that a new event will be added to the stream at the same time as each event.
-// If factor is set to 5.25, it is certain that 5 random events will be added at the same timestamp for each event in the stream and
-// there is a 25% chance of a sixth random event to be added t the stream.
-func ApplyAdditive(src []event.Event, maxX, maxY int, factor float64) []event.Event {
-	dst := make([]event.Event, 0)
-	r := randomEventGenerator{
-		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+func (g *Generator) newRandomEventGenerator(maxX, maxY int, factor float64) randomEventGenerator {
+	return randomEventGenerator{
+		random: g.random,
 		factor: factor,
 		maxX:   maxX,
 		maxY:   maxY,
 	}
+}
+
+// ApplyAdditive inserts additive noise events into event stream using the generator's source of randomness.
+// See the package level ApplyAdditive for a description of the factor argument.
+func (g *Generator) ApplyAdditive(src []event.Event, maxX, maxY int, factor float64) []event.Event {
+	dst, _ := g.ApplyAdditiveLabeled(src, maxX, maxY, factor)
+	return dst
+}
+
+// ApplyAdditiveLabeled works as ApplyAdditive and also returns a slice of labels where noisy[i] is true
+// if dst[i] is a noise event, so denoising filters can be evaluated against the ground truth.
+func (g *Generator) ApplyAdditiveLabeled(src []event.Event, maxX, maxY int, factor float64) (dst []event.Event, noisy []bool) {
+	dst = make([]event.Event, 0)
+	noisy = make([]bool, 0)
+	r := g.newRandomEventGenerator(maxX, maxY, factor)
 
 	for _, ev := range src {
 
 		dst = append(dst, ev)
-		dst = append(dst, r.createAdditionalNoisyEvents(ev.Ts)...)
+		noisy = append(noisy, false)
+		for _, n := range r.createAdditionalNoisyEvents(ev.Ts) {
+			dst = append(dst, n)
+			noisy = append(noisy, true)
+		}
 	}
+	return dst, noisy
+}
+
+// ApplyDegenerative replaces some events in an event stream with random events using the generator's source of randomness.
+// See the package level ApplyDegenerative for a description of the factor argument.
+func (g *Generator) ApplyDegenerative(src []event.Event, maxX, maxY int, factor float64) []event.Event {
+	dst, _ := g.ApplyDegenerativeLabeled(src, maxX, maxY, factor)
 	return dst
 }
 
-// ApplyDegenerative replaces some events in en event stream with random events at the same timestamp.
-// Since no new event is added, the resulting slice will have the same size as the source event slice.
-// The amount of affected events will vary depending on the factor value.
-// If factor is set to 0.0 no event will be affected.
-// If factor is set to 0.25, 25% of the events are likely to be affected.
-// If factor is set to 1.0, all events will be replaced by random events at the same timestamps.
-func ApplyDegenerative(src []event.Event, maxX, maxY int, factor float64) []event.Event {
-	dst := make([]event.Event, len(src))
-	r := randomEventGenerator{
-		random: rand.New(rand.NewSource(time.Now().UnixNano())),
-		factor: factor,
-		maxX:   maxX,
-		maxY:   maxY,
-	}
+// ApplyDegenerativeLabeled works as ApplyDegenerative and also returns a slice of labels where noisy[i] is true
+// if dst[i] is a random event which replaced a source event.
+func (g *Generator) ApplyDegenerativeLabeled(src []event.Event, maxX, maxY int, factor float64) (dst []event.Event, noisy []bool) {
+	dst = make([]event.Event, len(src))
+	noisy = make([]bool, len(src))
+	r := g.newRandomEventGenerator(maxX, maxY, factor)
 
 	for i, ev := range src {
 		if r.flipCoin() {
 			dst[i] = r.generateRandomEvent(ev.Ts)
+			noisy[i] = true
 		} else {
 			dst[i] = ev
 		}
 	}
-	return dst
+	return dst, noisy
+}
+
+// ApplyAdditive inserts additive noise events into event stream.  Since new events are added to the stream, applying additive noise will
+// result in a larger event slice.
+// The factor argument will determine the likelihood of a random event to be added to the stream for each existing event.
+// If factor is set to 0.0, no new events will be added.
+// If factor is set to 0.25 there is a 25% chance that a new event will be added to the stream at the same time as each event.
+// If factor is set to 5.25, it is certain that 5 random events will be added at the same timestamp for each event in the stream and
+// there is a 25% chance of a sixth random event to be added t the stream.
+// Noise is seeded from the current time. Use a Generator for reproducible results.
+func ApplyAdditive(src []event.Event, maxX, maxY int, factor float64) []event.Event {
+	return newTimeSeededGenerator().ApplyAdditive(src, maxX, maxY, factor)
+}
+
+// ApplyDegenerative replaces some events in en event stream with random events at the same timestamp.
+// Since no new event is added, the resulting slice will have the same size as the source event slice.
+// The amount of affected events will vary depending on the factor value.
+// If factor is set to 0.0 no event will be affected.
+// If factor is set to 0.25, 25% of the events are likely to be affected.
+// If factor is set to 1.0, all events will be replaced by random events at the same timestamps.
+// Noise is seeded from the current time. Use a Generator for reproducible results.
+func ApplyDegenerative(src []event.Event, maxX, maxY int, factor float64) []event.Event {
+	return newTimeSeededGenerator().ApplyDegenerative(src, maxX, maxY, factor)
 }
@@ -66,3 +66,64 @@ func TestApplyDegenerative(t *testing.T) {
 	}
 
 }
+
+func TestGeneratorReproducibility(t *testing.T) {
+
+	src := []event.Event{
+		{Coords: event.Point2D{X: 1, Y: 1}, Ts: 1, P: 1},
+		{Coords: event.Point2D{X: 2, Y: 2}, Ts: 2, P: 0},
+		{Coords: event.Point2D{X: 3, Y: 3}, Ts: 3, P: 1},
+		{Coords: event.Point2D{X: 4, Y: 4}, Ts: 4, P: 0},
+	}
+
+	additive1 := NewGenerator(42).ApplyAdditive(src, 100, 100, 2.5)
+	additive2 := NewGenerator(42).ApplyAdditive(src, 100, 100, 2.5)
+
+	if !reflect.DeepEqual(additive1, additive2) {
+		t.Errorf("Generators with the same seed should produce the same additive noise")
+	}
+
+	degenerative1 := NewGenerator(42).ApplyDegenerative(src, 100, 100, 0.5)
+	degenerative2 := NewGenerator(42).ApplyDegenerative(src, 100, 100, 0.5)
+
+	if !reflect.DeepEqual(degenerative1, degenerative2) {
+		t.Errorf("Generators with the same seed should produce the same degenerative noise")
+	}
+
+	if reflect.DeepEqual(additive1, NewGenerator(7).ApplyAdditive(src, 100, 100, 2.5)) {
+		t.Errorf("Generators with different seeds should produce different additive noise")
+	}
+
+}
+
+func TestApplyAdditiveLabeled(t *testing.T) {
+
+	src := []event.Event{
+		{Coords: event.Point2D{X: 1, Y: 1}, Ts: 1, P: 1},
+		{Coords: event.Point2D{X: 2, Y: 2}, Ts: 2, P: 0},
+	}
+
+	dst, noisy := NewGenerator(1).ApplyAdditiveLabeled(src, 10, 10, 2.0)
+
+	if len(dst) != 6 || len(noisy) != len(dst) {
+		t.Fatalf("When factor is set to 2.0 there should be 6 labeled events, got %d events and %d labels", len(dst), len(noisy))
+	}
+
+	signal := []event.Event{}
+	for i, ev := range dst {
+		if !noisy[i] {
+			signal = append(signal, ev)
+		}
+	}
+
+	if !reflect.DeepEqual(src, signal) {
+		t.Errorf("Events not labeled as noise should be the source events, got %v", signal)
+	}
+
+	_, noisy = NewGenerator(1).ApplyDegenerativeLabeled(src, 10, 10, 1.0)
+
+	if !reflect.DeepEqual(noisy, []bool{true, true}) {
+		t.Errorf("When factor is set to 1.0 every degenerative event should be labeled as noise, got %v", noisy)
+	}
+
+}
@@ -0,0 +1,190 @@
+package noise
+
+import (
+	"sort"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+// SensorModel describes the physical noise sources of an event sensor. Rates are expressed in events per second
+// while timestamps are expressed in microsseconds.
+type SensorModel struct {
+	Width  int // sensor width
+	Height int // sensor height
+
+	ShotRate float64 // background shot noise rate per pixel. Shot noise follows a Poisson process
+
+	LeakRate       float64 // mean leak event rate per pixel. Leak events are periodic ON events
+	LeakRateSpread float64 // relative standard deviation of the leak rate across pixels, 0.1 means 10%
+
+	HotPixels    []event.Point2D // pixels stuck firing at HotPixelRate
+	HotPixelRate float64         // hot pixel firing rate
+
+	DeadPixels []event.Point2D // pixels which never output events
+}
+
+func (g *Generator) randomPolarity() int {
+	return g.random.Intn(2)
+}
+
+// poissonTimes returns the arrival times of a Poisson process with rate events per second between start and end
+func (g *Generator) poissonTimes(rate float64, start, end int) []int {
+	ts := []int{}
+	if rate <= 0 {
+		return ts
+	}
+
+	usRate := rate / 1e6
+	t := float64(start) + g.random.ExpFloat64()/usRate
+	for t < float64(end) {
+		ts = append(ts, int(t))
+		t += g.random.ExpFloat64() / usRate
+	}
+	return ts
+}
+
+// ShotNoise generates background shot noise between start and end for every pixel of a width x height sensor.
+// Each pixel fires according to an independent Poisson process with rate events per second and random polarity,
+// so noise timestamps are not correlated with any signal. The resulting slice is sorted by timestamp.
+func (g *Generator) ShotNoise(width, height int, rate float64, start, end int) []event.Event {
+	dst := []event.Event{}
+
+	// The superposition of independent Poisson processes is a Poisson process with the summed rate
+	for _, ts := range g.poissonTimes(rate*float64(width*height), start, end) {
+		dst = append(dst, event.Event{
+			Coords: event.Point2D{X: g.random.Intn(width), Y: g.random.Intn(height)},
+			P:      g.randomPolarity(),
+			Ts:     ts,
+		})
+	}
+	return dst
+}
+
+// LeakNoise generates periodic leak events between start and end for every pixel of a width x height sensor.
+// Each pixel fires ON events with its own rate, drawn from a normal distribution with mean rate and standard deviation
+// rate*spread, and a random phase. The resulting slice is sorted by timestamp.
+func (g *Generator) LeakNoise(width, height int, rate, spread float64, start, end int) []event.Event {
+	dst := []event.Event{}
+	if rate <= 0 {
+		return dst
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r := rate * (1.0 + spread*g.random.NormFloat64())
+			if r <= 0 {
+				continue
+			}
+
+			period := 1e6 / r
+			for t := float64(start) + g.random.Float64()*period; t < float64(end); t += period {
+				dst = append(dst, event.Event{Coords: event.Point2D{X: x, Y: y}, P: 1, Ts: int(t)})
+			}
+		}
+	}
+
+	sortByTime(dst)
+	return dst
+}
+
+// HotPixelNoise generates events between start and end for each of the given hot pixels. Hot pixels fire according
+// to a Poisson process with rate events per second and random polarity. The resulting slice is sorted by timestamp.
+func (g *Generator) HotPixelNoise(pixels []event.Point2D, rate float64, start, end int) []event.Event {
+	dst := []event.Event{}
+
+	for _, px := range pixels {
+		for _, ts := range g.poissonTimes(rate, start, end) {
+			dst = append(dst, event.Event{Coords: px, P: g.randomPolarity(), Ts: ts})
+		}
+	}
+
+	sortByTime(dst)
+	return dst
+}
+
+// RandomPixels picks count distinct random pixels of a width x height sensor. It can be used to choose the hot and
+// dead pixels of a SensorModel.
+func (g *Generator) RandomPixels(width, height, count int) []event.Point2D {
+	total := width * height
+	if count > total {
+		count = total
+	}
+
+	dst := make([]event.Point2D, count)
+	for i, idx := range g.random.Perm(total)[:count] {
+		dst[i] = event.Point2D{X: idx % width, Y: idx / width}
+	}
+	return dst
+}
+
+// ApplySensorModel adds the noise described by model between start and end to the event stream. Events at dead pixels,
+// including noise events, are removed. The source slice is expected to be sorted by timestamp and the resulting slice
+// is also sorted by timestamp.
+func (g *Generator) ApplySensorModel(src []event.Event, model SensorModel, start, end int) []event.Event {
+	dst, _ := g.ApplySensorModelLabeled(src, model, start, end)
+	return dst
+}
+
+// ApplySensorModelLabeled works as ApplySensorModel and also returns a slice of labels where noisy[i] is true
+// if dst[i] is a noise event.
+func (g *Generator) ApplySensorModelLabeled(src []event.Event, model SensorModel, start, end int) (dst []event.Event, noisy []bool) {
+	type labeledEvent struct {
+		ev    event.Event
+		noisy bool
+	}
+
+	ev := make([]labeledEvent, 0, len(src))
+	add := func(events []event.Event, noisy bool) {
+		for _, e := range events {
+			ev = append(ev, labeledEvent{ev: e, noisy: noisy})
+		}
+	}
+
+	add(src, false)
+	add(g.ShotNoise(model.Width, model.Height, model.ShotRate, start, end), true)
+	add(g.LeakNoise(model.Width, model.Height, model.LeakRate, model.LeakRateSpread, start, end), true)
+	add(g.HotPixelNoise(model.HotPixels, model.HotPixelRate, start, end), true)
+
+	sort.SliceStable(ev, func(i, j int) bool { return ev[i].ev.Ts < ev[j].ev.Ts })
+
+	dead := make(map[event.Point2D]bool)
+	for _, px := range model.DeadPixels {
+		dead[px] = true
+	}
+
+	dst = make([]event.Event, 0, len(ev))
+	noisy = make([]bool, 0, len(ev))
+	for _, e := range ev {
+		if !dead[e.ev.Coords] {
+			dst = append(dst, e.ev)
+			noisy = append(noisy, e.noisy)
+		}
+	}
+	return dst, noisy
+}
+
+// RemoveDeadPixels drops every event located at one of the dead pixels
+func RemoveDeadPixels(src []event.Event, dead []event.Point2D) []event.Event {
+	deadMap := make(map[event.Point2D]bool)
+	for _, px := range dead {
+		deadMap[px] = true
+	}
+
+	dst := make([]event.Event, 0, len(src))
+	for _, ev := range src {
+		if !deadMap[ev.Coords] {
+			dst = append(dst, ev)
+		}
+	}
+	return dst
+}
+
+// ApplySensorModel adds the noise described by model between start and end to the event stream.
+// Noise is seeded from the current time. Use a Generator for reproducible results.
+func ApplySensorModel(src []event.Event, model SensorModel, start, end int) []event.Event {
+	return newTimeSeededGenerator().ApplySensorModel(src, model, start, end)
+}
+
+func sortByTime(ev []event.Event) {
+	sort.SliceStable(ev, func(i, j int) bool { return ev[i].Ts < ev[j].Ts })
+}
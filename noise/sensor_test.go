@@ -0,0 +1,129 @@
+package noise
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+func isSortedByTime(ev []event.Event) bool {
+	return sort.SliceIsSorted(ev, func(i, j int) bool { return ev[i].Ts < ev[j].Ts })
+}
+
+func TestShotNoise(t *testing.T) {
+	g := NewGenerator(1)
+
+	if len(g.ShotNoise(10, 10, 0.0, 0, 1e6)) != 0 {
+		t.Errorf("When rate is 0.0, no shot noise should be generated")
+	}
+
+	// 100 pixels at 10 Hz for 10 seconds should produce around 10000 events
+	shot := g.ShotNoise(10, 10, 10.0, 0, 10e6)
+
+	if math.Abs(float64(len(shot))-10000) > 500 {
+		t.Errorf("ShotNoise() generated %d events, expected around 10000", len(shot))
+	}
+
+	if !isSortedByTime(shot) {
+		t.Errorf("ShotNoise() should be sorted by timestamp")
+	}
+
+	for _, ev := range shot {
+		if ev.Coords.X < 0 || ev.Coords.X >= 10 || ev.Coords.Y < 0 || ev.Coords.Y >= 10 || ev.Ts < 0 || ev.Ts >= 10e6 {
+			t.Fatalf("ShotNoise() generated event %v outside of sensor or time range", ev)
+		}
+	}
+}
+
+func TestLeakNoise(t *testing.T) {
+	g := NewGenerator(1)
+
+	// Without spread every pixel fires exactly once per second
+	leak := g.LeakNoise(4, 4, 1.0, 0.0, 0, 3e6)
+
+	if len(leak) != 48 {
+		t.Errorf("LeakNoise() generated %d events, want 48", len(leak))
+	}
+
+	if !isSortedByTime(leak) {
+		t.Errorf("LeakNoise() should be sorted by timestamp")
+	}
+
+	for _, ev := range leak {
+		if ev.P != 1 {
+			t.Fatalf("LeakNoise() should only generate ON events, got %v", ev)
+		}
+	}
+}
+
+func TestHotPixelNoise(t *testing.T) {
+	g := NewGenerator(1)
+	hot := []event.Point2D{{X: 1, Y: 2}, {X: 3, Y: 4}}
+
+	ev := g.HotPixelNoise(hot, 1000.0, 0, 1e6)
+
+	counts := map[event.Point2D]int{}
+	for _, e := range ev {
+		counts[e.Coords]++
+	}
+
+	if len(counts) != 2 || counts[hot[0]] < 900 || counts[hot[1]] < 900 {
+		t.Errorf("HotPixelNoise() should only fire at hot pixels around 1000 times each, got %v", counts)
+	}
+}
+
+func TestRandomPixels(t *testing.T) {
+	px := NewGenerator(1).RandomPixels(5, 5, 30)
+
+	if len(px) != 25 {
+		t.Errorf("RandomPixels() should be limited to the sensor size, got %d pixels", len(px))
+	}
+
+	seen := map[event.Point2D]bool{}
+	for _, p := range px {
+		if seen[p] {
+			t.Fatalf("RandomPixels() returned duplicated pixel %v", p)
+		}
+		seen[p] = true
+	}
+}
+
+func TestApplySensorModel(t *testing.T) {
+	src := []event.Event{
+		{Coords: event.Point2D{X: 1, Y: 1}, Ts: 100, P: 1},
+		{Coords: event.Point2D{X: 2, Y: 2}, Ts: 200, P: 0},
+	}
+
+	model := SensorModel{
+		Width:        8,
+		Height:       8,
+		ShotRate:     5.0,
+		LeakRate:     0.5,
+		HotPixels:    []event.Point2D{{X: 3, Y: 3}},
+		HotPixelRate: 100.0,
+		DeadPixels:   []event.Point2D{{X: 2, Y: 2}, {X: 3, Y: 3}},
+	}
+
+	got := NewGenerator(3).ApplySensorModel(src, model, 0, 1e6)
+
+	if !isSortedByTime(got) {
+		t.Errorf("ApplySensorModel() should be sorted by timestamp")
+	}
+
+	for _, ev := range got {
+		if ev.Coords == model.DeadPixels[0] || ev.Coords == model.DeadPixels[1] {
+			t.Fatalf("ApplySensorModel() should not output events at dead pixels, got %v", ev)
+		}
+	}
+
+	if !reflect.DeepEqual(got, NewGenerator(3).ApplySensorModel(src, model, 0, 1e6)) {
+		t.Errorf("ApplySensorModel() should be reproducible with the same seed")
+	}
+
+	if !reflect.DeepEqual(RemoveDeadPixels(src, nil), src) {
+		t.Errorf("RemoveDeadPixels() without dead pixels should keep every event")
+	}
+}
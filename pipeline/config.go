@@ -0,0 +1,184 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// StageConfig describes a stage by its registered type and numeric parameters
+type StageConfig struct {
+	Type   string             `json:"type"`
+	Params map[string]float64 `json:"params,omitempty"`
+}
+
+// Config describes a pipeline, so experiments can be stored next to their results and reproduced
+type Config struct {
+	Stages []StageConfig `json:"stages"`
+}
+
+// Factory builds a Stage from its configuration parameters
+type Factory func(params map[string]float64) (Stage, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// Register makes a stage type available to configurations. Registering an existing type replaces it.
+func Register(stageType string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[stageType] = factory
+}
+
+// Types returns the registered stage types in alphabetical order
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	types := make([]string, 0, len(registry))
+	for t := range registry {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// FromConfig creates a Pipeline from a configuration
+func FromConfig(cfg Config) (*Pipeline, error) {
+	stages := make([]Stage, len(cfg.Stages))
+
+	for i, sc := range cfg.Stages {
+		registryMu.RLock()
+		factory, ok := registry[sc.Type]
+		registryMu.RUnlock()
+
+		if !ok {
+			return nil, fmt.Errorf("Unknown stage type %q", sc.Type)
+		}
+
+		s, err := factory(sc.Params)
+		if err != nil {
+			return nil, fmt.Errorf("Stage %d (%s): %v", i, sc.Type, err)
+		}
+		stages[i] = s
+	}
+
+	return New(stages...), nil
+}
+
+// Load reads a JSON configuration from r and creates the corresponding Pipeline
+func Load(r io.Reader) (*Pipeline, error) {
+	cfg := Config{}
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, err
+	}
+
+	return FromConfig(cfg)
+}
+
+type params map[string]float64
+
+func (p params) required(name string) (float64, error) {
+	v, ok := p[name]
+	if !ok {
+		return 0, fmt.Errorf("Missing parameter %q", name)
+	}
+	return v, nil
+}
+
+func (p params) optional(name string, def float64) float64 {
+	if v, ok := p[name]; ok {
+		return v
+	}
+	return def
+}
+
+// requiredInts reads several required integer parameters at once
+func (p params) requiredInts(names ...string) ([]int, error) {
+	values := make([]int, len(names))
+	for i, n := range names {
+		v, err := p.required(n)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = int(v)
+	}
+	return values, nil
+}
+
+func init() {
+	Register("refraction", func(p map[string]float64) (Stage, error) {
+		v, err := params(p).requiredInts("usTime")
+		if err != nil {
+			return nil, err
+		}
+		return Refraction(v[0]), nil
+	})
+	Register("spatiotemporal", func(p map[string]float64) (Stage, error) {
+		v, err := params(p).requiredInts("usTime")
+		if err != nil {
+			return nil, err
+		}
+		return SpatioTemporalRadius(int(params(p).optional("radius", 1)), v[0]), nil
+	})
+	Register("stcf", func(p map[string]float64) (Stage, error) {
+		v, err := params(p).requiredInts("radius", "usTime", "k")
+		if err != nil {
+			return nil, err
+		}
+		return STCF(v[0], v[1], v[2]), nil
+	})
+	Register("knoise", func(p map[string]float64) (Stage, error) {
+		v, err := params(p).requiredInts("usTime")
+		if err != nil {
+			return nil, err
+		}
+		return KNoise(v[0]), nil
+	})
+	Register("ynoise", func(p map[string]float64) (Stage, error) {
+		v, err := params(p).requiredInts("radius", "usTime", "threshold")
+		if err != nil {
+			return nil, err
+		}
+		return YNoise(v[0], v[1], v[2], int(params(p).optional("refractoryPeriod", 0))), nil
+	})
+	Register("hotpixels", func(p map[string]float64) (Stage, error) {
+		k, err := params(p).required("k")
+		if err != nil {
+			return nil, err
+		}
+		return HotPixels(k), nil
+	})
+	Register("bytime", func(p map[string]float64) (Stage, error) {
+		v, err := params(p).requiredInts("start", "end")
+		if err != nil {
+			return nil, err
+		}
+		return ByTime(v[0], v[1]), nil
+	})
+	Register("stabilize", func(p map[string]float64) (Stage, error) {
+		return Stabilizer(), nil
+	})
+	Register("additive", func(p map[string]float64) (Stage, error) {
+		f, err := params(p).required("factor")
+		if err != nil {
+			return nil, err
+		}
+		return AdditiveNoise(f, int64(params(p).optional("seed", 0))), nil
+	})
+	Register("degenerative", func(p map[string]float64) (Stage, error) {
+		f, err := params(p).required("factor")
+		if err != nil {
+			return nil, err
+		}
+		return DegenerativeNoise(f, int64(params(p).optional("seed", 0))), nil
+	})
+}
@@ -0,0 +1,133 @@
+// pipeline composes filters, transforms, noise and representations into reusable processing pipelines.
+// Stages operate on whole event captures, so stages which need the sensor size, such as spatio-temporal
+// filters, take it from the capture being processed.
+package pipeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+// Stage specifies a processing step of a Pipeline. Process must not modify the source capture events.
+type Stage interface {
+	Name() string
+	Process(evCap event.EventCapture) (event.EventCapture, error)
+}
+
+type funcStage struct {
+	name string
+	fn   func(event.EventCapture) (event.EventCapture, error)
+}
+
+func (f funcStage) Name() string {
+	return f.name
+}
+
+func (f funcStage) Process(evCap event.EventCapture) (event.EventCapture, error) {
+	return f.fn(evCap)
+}
+
+// Func creates a Stage from a function
+func Func(name string, fn func(event.EventCapture) (event.EventCapture, error)) Stage {
+	return funcStage{name: name, fn: fn}
+}
+
+// Events creates a Stage from a function processing an event slice, such as the filter package functions
+func Events(name string, fn func([]event.Event) []event.Event) Stage {
+	return Func(name, func(evCap event.EventCapture) (event.EventCapture, error) {
+		evCap.Events = fn(evCap.Events)
+		return evCap, nil
+	})
+}
+
+// StageStats records the activity of a stage. In streaming mode, counts and durations are accumulated over all chunks.
+type StageStats struct {
+	Name      string
+	InEvents  int
+	OutEvents int
+	Duration  time.Duration
+}
+
+// Pipeline runs a sequence of stages
+type Pipeline struct {
+	stages []Stage
+}
+
+// New creates a Pipeline running stages in the given order
+func New(stages ...Stage) *Pipeline {
+	return &Pipeline{stages: stages}
+}
+
+// Stages returns the stages of the pipeline
+func (p *Pipeline) Stages() []Stage {
+	return p.stages
+}
+
+func (p *Pipeline) newStats() []StageStats {
+	stats := make([]StageStats, len(p.stages))
+	for i, s := range p.stages {
+		stats[i].Name = s.Name()
+	}
+	return stats
+}
+
+func (p *Pipeline) run(evCap event.EventCapture, stats []StageStats) (event.EventCapture, error) {
+	for i, s := range p.stages {
+		in := len(evCap.Events)
+		start := time.Now()
+
+		var err error
+		evCap, err = s.Process(evCap)
+		if err != nil {
+			return event.EventCapture{}, err
+		}
+
+		stats[i].InEvents += in
+		stats[i].OutEvents += len(evCap.Events)
+		stats[i].Duration += time.Since(start)
+	}
+	return evCap, nil
+}
+
+// Run processes a whole capture in batch mode and returns the result with the statistics of each stage
+func (p *Pipeline) Run(evCap event.EventCapture) (event.EventCapture, []StageStats, error) {
+	stats := p.newStats()
+
+	res, err := p.run(evCap, stats)
+	if err != nil {
+		return event.EventCapture{}, stats, err
+	}
+	return res, stats, nil
+}
+
+// Stream processes chunks of a capture, such as consecutive time windows, as they arrive on in and sends the results
+// to out in the same order. Each chunk goes through the stages independently, so state such as the last timestamp of
+// a pixel does not carry over from one chunk to the next. Stream returns when in is closed, when a stage fails or
+// when ctx is cancelled. out is not closed by Stream.
+func (p *Pipeline) Stream(ctx context.Context, in <-chan event.EventCapture, out chan<- event.EventCapture) ([]StageStats, error) {
+	stats := p.newStats()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return stats, ctx.Err()
+		case evCap, ok := <-in:
+			if !ok {
+				return stats, nil
+			}
+
+			res, err := p.run(evCap, stats)
+			if err != nil {
+				return stats, err
+			}
+
+			select {
+			case out <- res:
+			case <-ctx.Done():
+				return stats, ctx.Err()
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/filter"
+	"github.com/ffardo/go-event-vision/motion"
+)
+
+var evCap = event.EventCapture{
+	Events: []event.Event{
+		{Coords: event.Point2D{X: 10, Y: 30}, Ts: 900, P: 1},
+		{Coords: event.Point2D{X: 10, Y: 30}, Ts: 1000, P: 1},
+		{Coords: event.Point2D{X: 11, Y: 30}, Ts: 1100, P: 1},
+		{Coords: event.Point2D{X: 20, Y: 2}, Ts: 3000, P: 0},
+	},
+	Width:  34,
+	Height: 34,
+}
+
+func TestPipelineRun(t *testing.T) {
+	p := New(Refraction(1000), SpatioTemporal(5000))
+
+	got, stats, err := p.Run(evCap)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := filter.SpatioTemporal(filter.ApplyRefraction(evCap.Events, 1000), evCap.Width, evCap.Height, 5000)
+	if !reflect.DeepEqual(got.Events, want) || got.Width != evCap.Width || got.Height != evCap.Height {
+		t.Errorf("Run() = %v, want %v", got, want)
+	}
+
+	wantStats := []StageStats{
+		{Name: "refraction", InEvents: 4, OutEvents: 3},
+		{Name: "spatiotemporal", InEvents: 3, OutEvents: len(want)},
+	}
+	for i := range stats {
+		stats[i].Duration = 0
+	}
+	if !reflect.DeepEqual(stats, wantStats) {
+		t.Errorf("Run() stats = %v, want %v", stats, wantStats)
+	}
+
+	failing := New(Refraction(1000), Func("fail", func(event.EventCapture) (event.EventCapture, error) {
+		return event.EventCapture{}, errors.New("failed")
+	}))
+	if _, _, err := failing.Run(evCap); err == nil {
+		t.Errorf("Run() should return the error of a failing stage")
+	}
+}
+
+func TestPipelineStream(t *testing.T) {
+	p := New(Refraction(1000))
+
+	in := make(chan event.EventCapture, 2)
+	out := make(chan event.EventCapture, 2)
+
+	in <- event.EventCapture{Events: evCap.Events[:2], Width: 34, Height: 34}
+	in <- event.EventCapture{Events: evCap.Events[2:], Width: 34, Height: 34}
+	close(in)
+
+	stats, err := p.Stream(context.Background(), in, out)
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+
+	if first := <-out; len(first.Events) != 1 {
+		t.Errorf("Stream() first chunk = %v, want a single event", first.Events)
+	}
+	if second := <-out; len(second.Events) != 2 {
+		t.Errorf("Stream() second chunk = %v, want two events", second.Events)
+	}
+	if stats[0].InEvents != 4 || stats[0].OutEvents != 3 {
+		t.Errorf("Stream() stats = %v, want accumulated counts", stats)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.Stream(ctx, make(chan event.EventCapture), out); err != context.Canceled {
+		t.Errorf("Stream() with cancelled context error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	cfg := `{"stages": [
+		{"type": "refraction", "params": {"usTime": 1000}},
+		{"type": "spatiotemporal", "params": {"usTime": 5000}},
+		{"type": "stabilize"}
+	]}`
+
+	p, err := Load(strings.NewReader(cfg))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	names := []string{}
+	for _, s := range p.Stages() {
+		names = append(names, s.Name())
+	}
+	if !reflect.DeepEqual(names, []string{"refraction", "spatiotemporal", "stabilize"}) {
+		t.Errorf("Load() stages = %v", names)
+	}
+
+	invalid := []string{
+		`{"stages": [{"type": "unknown"}]}`,
+		`{"stages": [{"type": "refraction"}]}`,
+		`{"stages": [{"type": "refraction", "params": {"usTime": 1000}}], "extra": 1}`,
+	}
+	for _, c := range invalid {
+		if _, err := Load(strings.NewReader(c)); err == nil {
+			t.Errorf("Load(%s) should return an error", c)
+		}
+	}
+}
+
+func TestConfigReproducibility(t *testing.T) {
+	cfg := Config{Stages: []StageConfig{{Type: "additive", Params: map[string]float64{"factor": 2, "seed": 7}}}}
+
+	p1, _ := FromConfig(cfg)
+	p2, _ := FromConfig(cfg)
+
+	got1, _, _ := p1.Run(evCap)
+	got2, _, _ := p2.Run(evCap)
+
+	if len(got1.Events) != 12 || !reflect.DeepEqual(got1, got2) {
+		t.Errorf("Pipelines created from the same configuration should produce the same noise")
+	}
+}
+
+func TestNoiseConcurrency(t *testing.T) {
+	stage := AdditiveNoise(2, 7)
+	other := event.EventCapture{Events: evCap.Events[:2], Width: 34, Height: 34}
+	want, _ := AdditiveNoise(2, 7).Process(evCap)
+
+	// runs of other captures, in any order, do not change the noise of a capture
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 1 {
+				stage.Process(other)
+				return
+			}
+			if got, _ := stage.Process(evCap); !reflect.DeepEqual(got, want) {
+				t.Errorf("AdditiveNoise() = %v, want %v", got, want)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got, _ := AdditiveNoise(2, 8).Process(evCap); reflect.DeepEqual(got, want) {
+		t.Errorf("AdditiveNoise() with another seed should produce other noise")
+	}
+}
+
+func TestMotionCompensation(t *testing.T) {
+	stage := MotionCompensation(motion.Compensator{
+		Model:    motion.Func(func(int) (float64, float64) { return -5, 0 }),
+		Clipping: motion.Discard,
+	})
+	p := New(stage)
+
+	events := []event.Event{{Coords: event.Point2D{X: 2, Y: 1}}, {Coords: event.Point2D{X: 12, Y: 1}}}
+
+	// the first capture must not fix the sensor size of the following ones
+	small, _, err := p.Run(event.EventCapture{Events: events, Width: 10, Height: 10})
+	if err != nil || len(small.Events) != 1 {
+		t.Errorf("Run() on 10x10 capture = %v, %v, want 1 event", small.Events, err)
+	}
+	large, _, err := p.Run(event.EventCapture{Events: events, Width: 20, Height: 10})
+	if err != nil || len(large.Events) != 2 {
+		t.Errorf("Run() on 20x10 capture = %v, %v, want 2 events", large.Events, err)
+	}
+	small, _, err = p.Run(event.EventCapture{Events: events, Width: 10, Height: 10})
+	if err != nil || len(small.Events) != 1 {
+		t.Errorf("Run() on 10x10 capture = %v, %v, want 1 event", small.Events, err)
+	}
+}
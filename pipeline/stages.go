@@ -0,0 +1,148 @@
+package pipeline
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+
+	"github.com/ffardo/go-event-vision"
+	"github.com/ffardo/go-event-vision/datasets/neuromorphic"
+	"github.com/ffardo/go-event-vision/filter"
+	"github.com/ffardo/go-event-vision/motion"
+	"github.com/ffardo/go-event-vision/noise"
+	"github.com/ffardo/go-event-vision/sae"
+)
+
+// Denoise creates a Stage from a filter.Denoiser
+func Denoise(name string, d filter.Denoiser) Stage {
+	return Events(name, d.Apply)
+}
+
+// sized creates a Stage from a filter.Denoiser which depends on the size of the processed capture
+func sized(name string, build func(width, height int) filter.Denoiser) Stage {
+	return Func(name, func(evCap event.EventCapture) (event.EventCapture, error) {
+		evCap.Events = build(evCap.Width, evCap.Height).Apply(evCap.Events)
+		return evCap, nil
+	})
+}
+
+// Refraction creates a Stage applying filter.ApplyRefraction
+func Refraction(usTime int) Stage {
+	return Denoise("refraction", filter.Refraction{UsTime: usTime})
+}
+
+// SpatioTemporal creates a Stage applying filter.SpatioTemporal with the size of the processed capture
+func SpatioTemporal(usTime int) Stage {
+	return SpatioTemporalRadius(1, usTime)
+}
+
+// SpatioTemporalRadius creates a Stage applying filter.SpatioTemporalRadius with the size of the processed capture
+func SpatioTemporalRadius(radius, usTime int) Stage {
+	return sized("spatiotemporal", func(width, height int) filter.Denoiser {
+		return filter.BackgroundActivity{Width: width, Height: height, Radius: radius, UsTime: usTime}
+	})
+}
+
+// STCF creates a Stage applying filter.STCF with the size of the processed capture
+func STCF(radius, usTime, k int) Stage {
+	return sized("stcf", func(width, height int) filter.Denoiser {
+		return filter.STCF{Width: width, Height: height, Radius: radius, UsTime: usTime, K: k}
+	})
+}
+
+// KNoise creates a Stage applying filter.KNoise with the size of the processed capture
+func KNoise(usTime int) Stage {
+	return sized("knoise", func(width, height int) filter.Denoiser {
+		return filter.KNoise{Width: width, Height: height, UsTime: usTime}
+	})
+}
+
+// YNoise creates a Stage applying filter.YNoise with the size of the processed capture
+func YNoise(radius, usTime, threshold, refractoryPeriod int) Stage {
+	return sized("ynoise", func(width, height int) filter.Denoiser {
+		return filter.YNoise{Width: width, Height: height, Radius: radius, UsTime: usTime, Threshold: threshold, RefractoryPeriod: refractoryPeriod}
+	})
+}
+
+// HotPixels creates a Stage applying filter.HotPixels with the size of the processed capture
+func HotPixels(k float64) Stage {
+	return sized("hotpixels", func(width, height int) filter.Denoiser {
+		return filter.HotPixel{Width: width, Height: height, K: k}
+	})
+}
+
+// ByTime creates a Stage applying filter.ByTime
+func ByTime(start, end int) Stage {
+	return Events("bytime", func(src []event.Event) []event.Event {
+		return filter.ByTime(src, start, end)
+	})
+}
+
+// Stabilizer creates a Stage applying neuromorphic.Stabilize
+func Stabilizer() Stage {
+	return Events("stabilize", neuromorphic.Stabilize)
+}
+
+// MotionCompensation creates a Stage applying a motion.Compensator. When the compensator has no sensor size,
+// the size of each processed capture is used.
+func MotionCompensation(c motion.Compensator) Stage {
+	return Func("motion", func(evCap event.EventCapture) (event.EventCapture, error) {
+		comp := c
+		if comp.Width == 0 && comp.Height == 0 {
+			comp.Width, comp.Height = evCap.Width, evCap.Height
+		}
+		evCap.Events = comp.Apply(evCap.Events)
+		return evCap, nil
+	})
+}
+
+// captureGenerator creates a noise generator for evCap, seeded from seed and a hash of the capture, so the noise
+// of a capture does not depend on the order or the concurrency of the runs
+func captureGenerator(seed int64, evCap event.EventCapture) *noise.Generator {
+	h := fnv.New64a()
+	b := make([]byte, 8)
+	write := func(v int) {
+		binary.LittleEndian.PutUint64(b, uint64(v))
+		h.Write(b)
+	}
+
+	write(evCap.Width)
+	write(evCap.Height)
+	for _, ev := range evCap.Events {
+		write(ev.Coords.X)
+		write(ev.Coords.Y)
+		write(ev.Ts)
+		write(ev.P)
+	}
+	return noise.NewGenerator(seed ^ int64(h.Sum64()))
+}
+
+// AdditiveNoise creates a Stage applying additive noise with the size of the processed capture.
+// The generator of each capture is derived from seed and the capture content, so the same captures always get the
+// same noise, regardless of the order of the runs or of concurrent workers such as datasets.Batch.
+func AdditiveNoise(factor float64, seed int64) Stage {
+	return Func("additive", func(evCap event.EventCapture) (event.EventCapture, error) {
+		evCap.Events = captureGenerator(seed, evCap).ApplyAdditive(evCap.Events, evCap.Width, evCap.Height, factor)
+		return evCap, nil
+	})
+}
+
+// DegenerativeNoise creates a Stage applying degenerative noise with the size of the processed capture.
+// The generator of each capture is derived from seed and the capture content, like in AdditiveNoise.
+func DegenerativeNoise(factor float64, seed int64) Stage {
+	return Func("degenerative", func(evCap event.EventCapture) (event.EventCapture, error) {
+		evCap.Events = captureGenerator(seed, evCap).ApplyDegenerative(evCap.Events, evCap.Width, evCap.Height, factor)
+		return evCap, nil
+	})
+}
+
+// SAE creates a Stage which builds a Surface of Active Events matrix of the processed capture and hands it to fn.
+// Events pass through the stage unchanged.
+func SAE(method string, fn func([][]int) error) Stage {
+	return Func("sae", func(evCap event.EventCapture) (event.EventCapture, error) {
+		m, err := sae.CreateMatrix(evCap.Events, method, evCap.Width, evCap.Height)
+		if err != nil {
+			return event.EventCapture{}, err
+		}
+		return evCap, fn(m)
+	})
+}
@@ -0,0 +1,175 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/ffardo/go-event-vision/flow"
+)
+
+// hsv converts a hue in radians, a saturation and a value in [0, 1] to RGB
+func hsv(h, s, v float64) color.RGBA {
+	h = math.Mod(h, 2*math.Pi)
+	if h < 0 {
+		h += 2 * math.Pi
+	}
+	h = h / (math.Pi / 3)
+
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h, 2)-1))
+	var r, g, b float64
+	switch int(h) {
+	case 0:
+		r, g = c, x
+	case 1:
+		r, g = x, c
+	case 2:
+		g, b = c, x
+	case 3:
+		g, b = x, c
+	case 4:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+
+	m := v - c
+	return color.RGBA{R: uint8((r + m) * 255), G: uint8((g + m) * 255), B: uint8((b + m) * 255), A: 255}
+}
+
+// FlowColor renders flow vectors with the color wheel convention, where hue encodes the direction and brightness
+// the speed, saturating at maxSpeed pixels per second. When maxSpeed is not positive, the highest speed of vectors
+// is used. Pixels without flow are black.
+func FlowColor(vectors []flow.Vector, width, height int, maxSpeed float64) *image.RGBA {
+	image := image.NewRGBA(image.Rectangle{image.Pt(0, 0), image.Pt(width, height)})
+
+	bg := color.RGBA{A: 255}
+	for i := 0; i < height; i++ {
+		for j := 0; j < width; j++ {
+			image.Set(j, i, bg)
+		}
+	}
+
+	if maxSpeed <= 0 {
+		for _, v := range vectors {
+			if s := v.Speed(); !math.IsInf(s, 0) && s > maxSpeed {
+				maxSpeed = s
+			}
+		}
+	}
+
+	for _, v := range vectors {
+		value := 0.0
+		if s := v.Speed(); maxSpeed > 0 && !math.IsNaN(s) {
+			value = math.Min(s/maxSpeed, 1)
+		}
+		image.Set(v.Event.Coords.X, v.Event.Coords.Y, hsv(math.Atan2(v.VY, v.VX), 1, value))
+	}
+
+	return image
+}
+
+// line draws a line from (x0, y0) to (x1, y1) with the Bresenham algorithm
+func line(img *image.RGBA, x0, y0, x1, y1 int, c color.RGBA) {
+	dx, dy := x1-x0, -(y1 - y0)
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy > 0 {
+		dy = -dy
+	}
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+	for {
+		img.Set(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// clip returns the fraction of v which can be added to p0 staying within [lo, hi]
+func clip(p0, v, lo, hi float64) float64 {
+	switch {
+	case v > 0:
+		return (hi - p0) / v
+	case v < 0:
+		return (lo - p0) / v
+	}
+	return 1
+}
+
+// clipVector shortens the vector (vx, vy) from (x0, y0) to end inside bounds, so outliers do not walk millions of
+// pixels outside of the image. It returns false if (x0, y0) is outside bounds or the vector is not finite.
+func clipVector(bounds image.Rectangle, x0, y0 int, vx, vy float64) (float64, float64, bool) {
+	if !image.Pt(x0, y0).In(bounds) || math.IsNaN(vx) || math.IsNaN(vy) || math.IsInf(vx, 0) || math.IsInf(vy, 0) {
+		return 0, 0, false
+	}
+
+	t := 1.0
+	t = math.Min(t, clip(float64(x0), vx, float64(bounds.Min.X), float64(bounds.Max.X-1)))
+	t = math.Min(t, clip(float64(y0), vy, float64(bounds.Min.Y), float64(bounds.Max.Y-1)))
+	return vx * t, vy * t, true
+}
+
+// FlowArrows draws the mean flow of each cell x cell pixels block on img as an arrow from the block center.
+// Arrows are scale pixels long per pixel per second of flow, and are shortened to end inside img.
+func FlowArrows(img *image.RGBA, vectors []flow.Vector, cell int, scale float64, c color.RGBA) {
+	if cell <= 0 {
+		return
+	}
+
+	type sum struct {
+		vx, vy float64
+		n      int
+	}
+	cells := map[image.Point]sum{}
+	for _, v := range vectors {
+		k := image.Pt(v.Event.Coords.X/cell, v.Event.Coords.Y/cell)
+		s := cells[k]
+		s.vx += v.VX
+		s.vy += v.VY
+		s.n++
+		cells[k] = s
+	}
+
+	for k, s := range cells {
+		x0, y0 := k.X*cell+cell/2, k.Y*cell+cell/2
+		vx, vy, ok := clipVector(img.Bounds(), x0, y0, s.vx/float64(s.n)*scale, s.vy/float64(s.n)*scale)
+		if !ok {
+			continue
+		}
+
+		x1, y1 := x0+int(math.Round(vx)), y0+int(math.Round(vy))
+		line(img, x0, y0, x1, y1, c)
+
+		// arrow head
+		length := math.Hypot(vx, vy)
+		if length < 1 {
+			continue
+		}
+		head := math.Max(length/3, 2)
+		angle := math.Atan2(vy, vx)
+		for _, side := range []float64{-1, 1} {
+			a := angle + math.Pi + side*math.Pi/6
+			line(img, x1, y1, x1+int(math.Round(head*math.Cos(a))), y1+int(math.Round(head*math.Sin(a))), c)
+		}
+	}
+}
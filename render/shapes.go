@@ -0,0 +1,26 @@
+package render
+
+import (
+	"image"
+	"image/color"
+)
+
+// Rectangles draws the outlines of rects on img, such as bounding boxes on top of Stream output.
+// Parts of the rectangles outside of img are not drawn.
+func Rectangles(img *image.RGBA, rects []image.Rectangle, c color.RGBA) {
+	for _, r := range rects {
+		r = r.Canon()
+		if r.Empty() {
+			continue
+		}
+
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.Set(x, r.Min.Y, c)
+			img.Set(x, r.Max.Y-1, c)
+		}
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			img.Set(r.Min.X, y, c)
+			img.Set(r.Max.X-1, y, c)
+		}
+	}
+}
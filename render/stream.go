@@ -27,3 +27,10 @@ func Stream(events []event.Event, width, height int, background, positive, negat
 
 	return image
 }
+
+// Events draws events on img with a single color, such as detected corners on top of Stream output
+func Events(img *image.RGBA, events []event.Event, c color.RGBA) {
+	for _, e := range events {
+		img.Set(e.Coords.X, e.Coords.Y, c)
+	}
+}
@@ -0,0 +1,28 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/ffardo/go-event-vision/tracking"
+)
+
+// Tracks draws records on img, such as the records of a window on top of Stream output. Each track is drawn as a
+// square of twice its size around its position and a line along its velocity, scale pixels long per pixel per
+// second, shortened to end inside img.
+func Tracks(img *image.RGBA, records []tracking.Record, scale float64, c color.RGBA) {
+	rects := make([]image.Rectangle, 0, len(records))
+	for _, r := range records {
+		x, y := int(math.Round(r.X)), int(math.Round(r.Y))
+		half := int(math.Ceil(r.Size))
+		if half < 1 {
+			half = 1
+		}
+		rects = append(rects, image.Rect(x-half, y-half, x+half+1, y+half+1))
+		if vx, vy, ok := clipVector(img.Bounds(), x, y, r.VX*scale, r.VY*scale); ok {
+			line(img, x, y, x+int(math.Round(vx)), y+int(math.Round(vy)), c)
+		}
+	}
+	Rectangles(img, rects, c)
+}
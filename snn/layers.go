@@ -0,0 +1,148 @@
+package snn
+
+import (
+	"errors"
+)
+
+// Dense is a fully connected layer of Size neurons, with output shape 1 x 1 x Size
+type Dense struct {
+	Shape   Shape     // input shape
+	Size    int       // number of neurons
+	LIF     LIF       // neuron parameters
+	Weights []float64 // weights indexed by [post][pre], with Size x Shape.Size() values
+}
+
+// NewDense creates a Dense layer with zero weights
+func NewDense(in Shape, size int, lif LIF) (*Dense, error) {
+	if in.Size() <= 0 || size <= 0 {
+		return nil, errors.New("Invalid layer size")
+	}
+	return &Dense{Shape: in, Size: size, LIF: lif, Weights: make([]float64, size*in.Size())}, nil
+}
+
+// In returns the input shape
+func (d *Dense) In() Shape {
+	return d.Shape
+}
+
+// Out returns the output shape
+func (d *Dense) Out() Shape {
+	return Shape{Channels: 1, Height: 1, Width: d.Size}
+}
+
+// Neurons returns the neuron parameters
+func (d *Dense) Neurons() LIF {
+	return d.LIF
+}
+
+func (d *Dense) valid() bool {
+	return d.Shape.positive() && d.Size > 0 && len(d.Weights) == d.Size*d.Shape.Size()
+}
+
+func (d *Dense) connections(pre int, visit func(post int, w *float64)) {
+	n := d.Shape.Size()
+	for post := 0; post < d.Size; post++ {
+		visit(post, &d.Weights[post*n+pre])
+	}
+}
+
+func (d *Dense) inputs(post int, visit func(pre int, w *float64)) {
+	n := d.Shape.Size()
+	for pre := 0; pre < n; pre++ {
+		visit(pre, &d.Weights[post*n+pre])
+	}
+}
+
+// Conv is a convolutional layer without padding, where each output channel shares a Kernel x Kernel weight
+// kernel per input channel
+type Conv struct {
+	Shape    Shape     // input shape
+	Channels int       // number of output channels
+	Kernel   int       // kernel size
+	Stride   int       // stride, 1 when zero
+	LIF      LIF       // neuron parameters
+	Weights  []float64 // weights indexed by [output channel][input channel][ky][kx]
+}
+
+// NewConv creates a Conv layer with zero weights. The kernel must fit in the input.
+func NewConv(in Shape, channels, kernel, stride int, lif LIF) (*Conv, error) {
+	if in.Channels <= 0 || in.Height <= 0 || in.Width <= 0 || channels <= 0 || kernel <= 0 {
+		return nil, errors.New("Invalid layer size")
+	}
+	if kernel > in.Height || kernel > in.Width {
+		return nil, errors.New("Kernel larger than the layer input")
+	}
+	c := &Conv{Shape: in, Channels: channels, Kernel: kernel, Stride: stride, LIF: lif}
+	c.Weights = make([]float64, channels*in.Channels*kernel*kernel)
+	return c, nil
+}
+
+func (c *Conv) stride() int {
+	if c.Stride <= 0 {
+		return 1
+	}
+	return c.Stride
+}
+
+// In returns the input shape
+func (c *Conv) In() Shape {
+	return c.Shape
+}
+
+// Out returns the output shape
+func (c *Conv) Out() Shape {
+	s := c.stride()
+	return Shape{
+		Channels: c.Channels,
+		Height:   (c.Shape.Height-c.Kernel)/s + 1,
+		Width:    (c.Shape.Width-c.Kernel)/s + 1,
+	}
+}
+
+// Neurons returns the neuron parameters
+func (c *Conv) Neurons() LIF {
+	return c.LIF
+}
+
+func (c *Conv) valid() bool {
+	return c.Shape.positive() && c.Channels > 0 && c.Kernel > 0 && c.Kernel <= c.Shape.Height &&
+		c.Kernel <= c.Shape.Width && len(c.Weights) == c.Channels*c.Shape.Channels*c.Kernel*c.Kernel
+}
+
+func (c *Conv) weight(oc, ic, ky, kx int) *float64 {
+	return &c.Weights[((oc*c.Shape.Channels+ic)*c.Kernel+ky)*c.Kernel+kx]
+}
+
+func (c *Conv) connections(pre int, visit func(post int, w *float64)) {
+	out, s := c.Out(), c.stride()
+	ic, y, x := c.Shape.coords(pre)
+
+	for ky := 0; ky < c.Kernel; ky++ {
+		oy := y - ky
+		if oy < 0 || oy%s != 0 || oy/s >= out.Height {
+			continue
+		}
+		for kx := 0; kx < c.Kernel; kx++ {
+			ox := x - kx
+			if ox < 0 || ox%s != 0 || ox/s >= out.Width {
+				continue
+			}
+			for oc := 0; oc < c.Channels; oc++ {
+				visit(out.index(oc, oy/s, ox/s), c.weight(oc, ic, ky, kx))
+			}
+		}
+	}
+}
+
+func (c *Conv) inputs(post int, visit func(pre int, w *float64)) {
+	s := c.stride()
+	oc, oy, ox := c.Out().coords(post)
+
+	for ic := 0; ic < c.Shape.Channels; ic++ {
+		for ky := 0; ky < c.Kernel; ky++ {
+			for kx := 0; kx < c.Kernel; kx++ {
+				visit(c.Shape.index(ic, oy*s+ky, ox*s+kx), c.weight(oc, ic, ky, kx))
+			}
+		}
+	}
+}
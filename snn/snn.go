@@ -0,0 +1,316 @@
+/*
+snn simulates spiking neural networks of leaky integrate-and-fire (LIF) neurons, driven event by event.
+
+Each input event is mapped to an input neuron, whose spike is propagated through the layers of the network. A LIF
+neuron integrates the weights of its presynaptic spikes into its membrane potential, which leaks exponentially
+toward zero. When the potential reaches the threshold the neuron spikes, its potential is reset and it ignores
+inputs during its refractory period. Synapses have no delay, so a spike reaches the output at the timestamp of the
+input event which caused it.
+
+Spikes are represented as events, with the neuron position in the layer as coordinates and its channel as
+polarity, so outputs can be rendered, filtered or fed to other layers. Weights can optionally be learned with
+pair-based spike-timing-dependent plasticity (STDP).
+*/
+package snn
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+// Shape is the size of a layer of neurons, indexed by channel, row and column
+type Shape struct {
+	Channels int
+	Height   int
+	Width    int
+}
+
+// Size returns the number of neurons
+func (s Shape) Size() int {
+	return s.Channels * s.Height * s.Width
+}
+
+func (s Shape) positive() bool {
+	return s.Channels > 0 && s.Height > 0 && s.Width > 0
+}
+
+func (s Shape) index(c, y, x int) int {
+	return (c*s.Height+y)*s.Width + x
+}
+
+func (s Shape) coords(i int) (c, y, x int) {
+	return i / (s.Height * s.Width), i / s.Width % s.Height, i % s.Width
+}
+
+// LIF holds the parameters of the neurons of a layer
+type LIF struct {
+	Threshold    float64 // membrane potential which triggers a spike
+	Reset        float64 // membrane potential after a spike
+	TauUs        float64 // leak time constant, in microsseconds. 0 disables the leak
+	RefractoryUs int     // period after a spike where inputs are ignored, in microsseconds
+}
+
+// Input maps events to input neurons
+type Input struct {
+	Width    int  // sensor width
+	Height   int  // sensor height
+	Scale    int  // each input neuron receives the events of a Scale x Scale pixels block, 1 when zero
+	Polarity bool // events of each polarity are mapped to separate channels
+}
+
+func (in Input) scale() int {
+	if in.Scale <= 0 {
+		return 1
+	}
+	return in.Scale
+}
+
+// Shape returns the shape of the input neurons
+func (in Input) Shape() Shape {
+	s := in.scale()
+	shape := Shape{Channels: 1, Height: (in.Height + s - 1) / s, Width: (in.Width + s - 1) / s}
+	if in.Polarity {
+		shape.Channels = 2
+	}
+	return shape
+}
+
+// Map returns the input neuron of ev, or false if ev is outside of the sensor
+func (in Input) Map(ev event.Event) (int, bool) {
+	if ev.Coords.X < 0 || ev.Coords.X >= in.Width || ev.Coords.Y < 0 || ev.Coords.Y >= in.Height {
+		return 0, false
+	}
+
+	c, s := 0, in.scale()
+	if in.Polarity {
+		c = ev.P & 1
+	}
+	return in.Shape().index(c, ev.Coords.Y/s, ev.Coords.X/s), true
+}
+
+// Layer is a set of LIF neurons connected to the neurons of the previous layer
+type Layer interface {
+	In() Shape
+	Out() Shape
+	Neurons() LIF
+	// connections calls visit with every postsynaptic neuron of pre and the weight of the synapse
+	connections(pre int, visit func(post int, w *float64))
+	// inputs calls visit with every presynaptic neuron of post and the weight of the synapse
+	inputs(post int, visit func(pre int, w *float64))
+	// valid returns false if the parameters or the weights of the layer do not match its shape
+	valid() bool
+}
+
+// Uniform sets weights to values drawn uniformly from [lo, hi), with a seeded generator
+func Uniform(weights []float64, seed int64, lo, hi float64) {
+	random := rand.New(rand.NewSource(seed))
+	for i := range weights {
+		weights[i] = lo + random.Float64()*(hi-lo)
+	}
+}
+
+// STDP holds the parameters of pair-based spike-timing-dependent plasticity. A postsynaptic spike potentiates
+// the synapses of the presynaptic neurons which spiked before it, and a presynaptic spike depresses the synapses
+// of the postsynaptic neurons which spiked before it. Only the most recent spike of each neuron is paired.
+type STDP struct {
+	APlus      float64 // potentiation amplitude
+	AMinus     float64 // depression amplitude
+	TauPlusUs  float64 // potentiation time constant, in microsseconds
+	TauMinusUs float64 // depression time constant, in microsseconds
+	WMin, WMax float64 // weights are clamped to [WMin, WMax]
+}
+
+func (s *STDP) update(w *float64, dw float64) {
+	*w += dw
+	if *w < s.WMin {
+		*w = s.WMin
+	} else if *w > s.WMax {
+		*w = s.WMax
+	}
+}
+
+const never = math.MinInt32
+
+// state is the state of the neurons of a layer
+type state struct {
+	potential  []float64
+	updated    []int // timestamp of the last potential update
+	refractory []int // inputs before this timestamp are ignored
+	spiked     []int // timestamp of the last spike
+}
+
+func newState(size int) *state {
+	s := &state{
+		potential:  make([]float64, size),
+		updated:    make([]int, size),
+		refractory: make([]int, size),
+		spiked:     make([]int, size),
+	}
+	for i := range s.spiked {
+		s.updated[i], s.refractory[i], s.spiked[i] = never, never, never
+	}
+	return s
+}
+
+// integrate adds w to the potential of neuron i at ts and returns true if it spikes
+func (s *state) integrate(i, ts int, w float64, lif LIF) bool {
+	if ts < s.refractory[i] {
+		return false
+	}
+	if lif.TauUs > 0 && s.updated[i] != never {
+		s.potential[i] *= math.Exp(-float64(ts-s.updated[i]) / lif.TauUs)
+	}
+	s.updated[i] = ts
+	s.potential[i] += w
+
+	if s.potential[i] < lif.Threshold {
+		return false
+	}
+	s.potential[i] = lif.Reset
+	s.refractory[i] = ts + lif.RefractoryUs
+	s.spiked[i] = ts
+	return true
+}
+
+// Network is a feed forward network of LIF layers
+type Network struct {
+	Input  Input
+	Layers []Layer
+	STDP   *STDP // weights are learned when not nil
+
+	inputSpiked []int
+	states      []*state
+}
+
+// NewNetwork creates a Network, checking each layer is valid, such as layers built as literals, and its shape
+// matches its previous layer
+func NewNetwork(input Input, layers ...Layer) (*Network, error) {
+	if len(layers) == 0 || input.Shape().Size() == 0 {
+		return nil, errors.New("Invalid network")
+	}
+
+	shape := input.Shape()
+	for _, l := range layers {
+		if !l.valid() || !l.Out().positive() {
+			return nil, errors.New("Invalid layer")
+		}
+		if l.In() != shape {
+			return nil, errors.New("Layer input shape does not match the previous layer")
+		}
+		shape = l.Out()
+	}
+
+	n := &Network{Input: input, Layers: layers}
+	n.Reset()
+	return n, nil
+}
+
+// Reset clears the state of the neurons, keeping the weights, so the network can process a new sample
+func (n *Network) Reset() {
+	n.inputSpiked = make([]int, n.Input.Shape().Size())
+	for i := range n.inputSpiked {
+		n.inputSpiked[i] = never
+	}
+
+	n.states = make([]*state, len(n.Layers))
+	for i, l := range n.Layers {
+		n.states[i] = newState(l.Out().Size())
+	}
+}
+
+type spike struct {
+	layer  int // -1 for input neurons
+	neuron int
+}
+
+// Process propagates ev through the network and returns the spikes of the output layer
+func (n *Network) Process(ev event.Event) []event.Event {
+	dst := []event.Event{}
+	i, ok := n.Input.Map(ev)
+	if !ok {
+		return dst
+	}
+
+	n.inputSpiked[i] = ev.Ts
+	queue := []spike{{layer: -1, neuron: i}}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+
+		next := s.layer + 1
+		if next == len(n.Layers) {
+			c, y, x := n.Layers[s.layer].Out().coords(s.neuron)
+			dst = append(dst, event.Event{Coords: event.Point2D{X: x, Y: y}, Ts: ev.Ts, P: c})
+			continue
+		}
+
+		l, st := n.Layers[next], n.states[next]
+		lif := l.Neurons()
+		l.connections(s.neuron, func(post int, w *float64) {
+			if n.STDP != nil && st.spiked[post] != never {
+				n.STDP.update(w, -n.STDP.AMinus*math.Exp(-float64(ev.Ts-st.spiked[post])/n.STDP.TauMinusUs))
+			}
+			if st.integrate(post, ev.Ts, *w, lif) {
+				if n.STDP != nil {
+					n.learn(next, post, ev.Ts)
+				}
+				queue = append(queue, spike{layer: next, neuron: post})
+			}
+		})
+	}
+
+	return dst
+}
+
+// learn potentiates the synapses of the presynaptic neurons which spiked before the spike of post in layer l
+func (n *Network) learn(l, post, ts int) {
+	spiked := n.inputSpiked
+	if l > 0 {
+		spiked = n.states[l-1].spiked
+	}
+	n.Layers[l].inputs(post, func(pre int, w *float64) {
+		if spiked[pre] != never {
+			n.STDP.update(w, n.STDP.APlus*math.Exp(-float64(ts-spiked[pre])/n.STDP.TauPlusUs))
+		}
+	})
+}
+
+// Run processes src, which is expected to be sorted by timestamp, and returns the spikes of the output layer.
+// The state of the network is kept, so a sample can be processed in several calls.
+func (n *Network) Run(src []event.Event) []event.Event {
+	dst := []event.Event{}
+	for _, ev := range src {
+		dst = append(dst, n.Process(ev)...)
+	}
+	return dst
+}
+
+// Counts returns the number of spikes of each neuron of shape
+func Counts(spikes []event.Event, shape Shape) []int {
+	counts := make([]int, shape.Size())
+	for _, s := range spikes {
+		if s.P >= 0 && s.P < shape.Channels && s.Coords.Y >= 0 && s.Coords.Y < shape.Height &&
+			s.Coords.X >= 0 && s.Coords.X < shape.Width {
+			counts[shape.index(s.P, s.Coords.Y, s.Coords.X)]++
+		}
+	}
+	return counts
+}
+
+// Classify resets the network, runs src and returns the output neuron with the most spikes, or -1 if no output
+// neuron spiked. Ties are resolved to the first neuron.
+func (n *Network) Classify(src []event.Event) int {
+	n.Reset()
+	counts := Counts(n.Run(src), n.Layers[len(n.Layers)-1].Out())
+
+	best := -1
+	for i, c := range counts {
+		if c > 0 && (best == -1 || c > counts[best]) {
+			best = i
+		}
+	}
+	return best
+}
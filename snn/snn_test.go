@@ -0,0 +1,194 @@
+package snn
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+func ev(x, y, ts, p int) event.Event {
+	return event.Event{Coords: event.Point2D{X: x, Y: y}, Ts: ts, P: p}
+}
+
+func TestInput_Map(t *testing.T) {
+	in := Input{Width: 34, Height: 34, Scale: 2, Polarity: true}
+	if got, want := in.Shape(), (Shape{Channels: 2, Height: 17, Width: 17}); got != want {
+		t.Errorf("Input.Shape() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		name  string
+		ev    event.Event
+		want  int
+		valid bool
+	}{
+		{name: "Test first pixel", ev: ev(0, 0, 0, 0), want: 0, valid: true},
+		{name: "Test scaled pixel", ev: ev(5, 3, 0, 0), want: 17 + 2, valid: true},
+		{name: "Test polarity channel", ev: ev(1, 1, 0, 1), want: 17 * 17, valid: true},
+		{name: "Test outside of sensor", ev: ev(34, 0, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := in.Map(tt.ev)
+			if ok != tt.valid || (ok && got != tt.want) {
+				t.Errorf("Input.Map() = %v, %v, want %v, %v", got, ok, tt.want, tt.valid)
+			}
+		})
+	}
+}
+
+func TestNetwork_Process(t *testing.T) {
+	in := Input{Width: 1, Height: 1}
+	dense, _ := NewDense(in.Shape(), 1, LIF{Threshold: 1, TauUs: 1000, RefractoryUs: 100})
+	dense.Weights[0] = 0.6
+	n, err := NewNetwork(in, dense)
+	if err != nil {
+		t.Fatalf("NewNetwork() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		ts    int
+		spike bool
+	}{
+		{name: "Test below threshold", ts: 0},
+		{name: "Test integration", ts: 10, spike: true},
+		{name: "Test refractory period", ts: 50},
+		{name: "Test reset", ts: 200},
+		{name: "Test leak", ts: 5000},
+		{name: "Test integration after leak", ts: 5010, spike: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := n.Process(ev(0, 0, tt.ts, 1))
+			want := []event.Event{}
+			if tt.spike {
+				want = append(want, ev(0, 0, tt.ts, 0))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Network.Process() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestNewNetwork(t *testing.T) {
+	in := Input{Width: 8, Height: 8}
+	conv, _ := NewConv(in.Shape(), 2, 3, 1, LIF{Threshold: 1})
+	dense, _ := NewDense(Shape{Channels: 2, Height: 6, Width: 6}, 4, LIF{Threshold: 1})
+	if _, err := NewNetwork(in, conv, dense); err != nil {
+		t.Errorf("NewNetwork() error = %v", err)
+	}
+	if _, err := NewNetwork(in, dense); err == nil {
+		t.Errorf("NewNetwork() accepted mismatched shapes")
+	}
+
+	small := Input{Width: 3, Height: 3}
+	invalid := []Layer{
+		&Conv{Shape: small.Shape(), Channels: 1, Kernel: 5, Weights: make([]float64, 25)},
+		&Conv{Shape: small.Shape(), Channels: 1, Kernel: 2},
+		&Dense{Shape: small.Shape(), Size: 2, Weights: make([]float64, 9)},
+		&Dense{Shape: small.Shape()},
+	}
+	for _, l := range invalid {
+		if _, err := NewNetwork(small, l); err == nil {
+			t.Errorf("NewNetwork() accepted the invalid layer %+v", l)
+		}
+	}
+}
+
+func TestNewConv(t *testing.T) {
+	invalid := []struct {
+		in             Shape
+		kernel, stride int
+	}{
+		{in: Shape{Channels: 1, Height: 4, Width: 4}, kernel: 5, stride: 2},
+		{in: Shape{Channels: 1, Height: 8, Width: 4}, kernel: 5, stride: 1},
+		{in: Shape{Channels: 1, Height: -4, Width: -4}, kernel: 3, stride: 1},
+		{in: Shape{Channels: 1, Height: 4, Width: 4}, kernel: 0, stride: 1},
+	}
+	for _, tt := range invalid {
+		if _, err := NewConv(tt.in, 1, tt.kernel, tt.stride, LIF{}); err == nil {
+			t.Errorf("NewConv(%v, kernel %v, stride %v) should return an error", tt.in, tt.kernel, tt.stride)
+		}
+	}
+
+	conv, err := NewConv(Shape{Channels: 1, Height: 4, Width: 4}, 1, 4, 2, LIF{})
+	if err != nil || conv.Out() != (Shape{Channels: 1, Height: 1, Width: 1}) {
+		t.Errorf("NewConv() = %v, %v for a kernel of the input size", conv, err)
+	}
+}
+
+func TestConv_connections(t *testing.T) {
+	conv, err := NewConv(Shape{Channels: 2, Height: 7, Width: 6}, 3, 3, 2, LIF{})
+	if err != nil {
+		t.Fatalf("NewConv() error = %v", err)
+	}
+	if got, want := conv.Out(), (Shape{Channels: 3, Height: 3, Width: 2}); got != want {
+		t.Fatalf("Conv.Out() = %v, want %v", got, want)
+	}
+
+	type synapse struct {
+		pre, post int
+		w         *float64
+	}
+	forward, backward := map[synapse]bool{}, map[synapse]bool{}
+	for pre := 0; pre < conv.In().Size(); pre++ {
+		conv.connections(pre, func(post int, w *float64) { forward[synapse{pre, post, w}] = true })
+	}
+	for post := 0; post < conv.Out().Size(); post++ {
+		conv.inputs(post, func(pre int, w *float64) { backward[synapse{pre, post, w}] = true })
+	}
+
+	if len(forward) != conv.Out().Size()*2*9 || !reflect.DeepEqual(forward, backward) {
+		t.Errorf("Conv connections = %v synapses, inputs = %v synapses", len(forward), len(backward))
+	}
+}
+
+func TestSTDP(t *testing.T) {
+	in := Input{Width: 2, Height: 1}
+	dense, _ := NewDense(in.Shape(), 1, LIF{Threshold: 1})
+	dense.Weights[0], dense.Weights[1] = 0.5, 1
+	n, _ := NewNetwork(in, dense)
+	n.STDP = &STDP{APlus: 0.1, AMinus: 0.1, TauPlusUs: 1000, TauMinusUs: 1000, WMin: 0, WMax: 1}
+
+	// pixel 0 spikes before the output neuron, and pixel 1 after it
+	n.Run([]event.Event{ev(0, 0, 0, 0), ev(1, 0, 1000, 0), ev(1, 0, 2000, 0)})
+
+	// the output spike at 1000 potentiates pixel 0 and pixel 1, which saturates, and the last event of pixel 1
+	// is depressed before being integrated
+	want := []float64{0.5 + 0.1*math.Exp(-1), 1 - 0.1*math.Exp(-1)}
+	for i := range want {
+		if math.Abs(dense.Weights[i]-want[i]) > 1e-9 {
+			t.Errorf("STDP weights = %v, want %v", dense.Weights, want)
+			break
+		}
+	}
+}
+
+func TestNetwork_Classify(t *testing.T) {
+	in := Input{Width: 4, Height: 4}
+	dense, _ := NewDense(in.Shape(), 2, LIF{Threshold: 2, TauUs: 10000})
+	for pre := 0; pre < 16; pre++ {
+		post := 0
+		if pre%4 >= 2 {
+			post = 1
+		}
+		dense.Weights[post*16+pre] = 1
+	}
+	n, _ := NewNetwork(in, dense)
+
+	left := []event.Event{ev(0, 0, 0, 1), ev(1, 2, 10, 1), ev(0, 3, 20, 0), ev(3, 3, 30, 0)}
+	right := []event.Event{ev(2, 0, 0, 1), ev(3, 2, 10, 1), ev(2, 1, 20, 0)}
+	if got := n.Classify(left); got != 0 {
+		t.Errorf("Network.Classify() = %v, want 0", got)
+	}
+	if got := n.Classify(right); got != 1 {
+		t.Errorf("Network.Classify() = %v, want 1", got)
+	}
+	if got := n.Classify(left[:1]); got != -1 {
+		t.Errorf("Network.Classify() = %v, want -1", got)
+	}
+}
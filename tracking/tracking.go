@@ -0,0 +1,283 @@
+/*
+tracking implements cluster based tracking of moving objects on event streams, in the style of Litzenberger et
+al., "Embedded vision system for real-time object tracking using an asynchronous transient vision sensor" (2006).
+
+Each event is assigned to the nearest cluster within a radius, which moves toward the event. Events without a
+cluster spawn new ones. The support of a cluster decays over time, so clusters become visible once enough events
+support them and are removed when they are no longer fed. Visible clusters are reported as track records once per
+time window.
+*/
+package tracking
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+// Config holds the tracker parameters
+type Config struct {
+	Radius         float64 // events within Radius pixels of a cluster center are assigned to it
+	MixingFactor   float64 // weight of an event when updating the cluster position and size, 0.05 when zero
+	DecayUs        int     // time constant of the support decay, in microsseconds
+	VisibleSupport float64 // minimum support of reported clusters
+	KillSupport    float64 // clusters whose support decays below KillSupport are removed, 0.1 when zero
+	MaxClusters    int     // maximum number of clusters, unlimited when zero
+	WindowUs       int     // period of the track records, in microsseconds
+}
+
+// Cluster is a tracked group of events
+type Cluster struct {
+	ID      int
+	X, Y    float64 // center position
+	Size    float64 // mean distance of assigned events to the center
+	Support float64 // decayed number of assigned events
+	LastTs  int     // timestamp of the last assigned event
+
+	windowX, windowY float64 // position at the end of the last window
+	windowTs         int
+	windowed         bool // the cluster was alive at the end of a window
+}
+
+// Record is the state of a track at the end of a time window
+type Record struct {
+	TrackID int     `json:"trackId"`
+	Ts      int     `json:"ts"`
+	X       float64 `json:"x"`
+	Y       float64 `json:"y"`
+	VX      float64 `json:"vx"` // velocity over the window, in pixels per second. Zero during the first window of a track
+	VY      float64 `json:"vy"`
+	Size    float64 `json:"size"`
+	Support float64 `json:"support"`
+}
+
+// Tracker tracks clusters of events. Events must be processed in timestamp order.
+type Tracker struct {
+	config     Config
+	clusters   []*Cluster
+	nextID     int
+	nextWindow int
+	started    bool
+}
+
+// NewTracker creates a Tracker
+func NewTracker(config Config) (*Tracker, error) {
+	if config.Radius <= 0 || config.DecayUs <= 0 || config.WindowUs <= 0 || config.KillSupport < 0 {
+		return nil, errors.New("Invalid tracker configuration")
+	}
+	if config.MixingFactor == 0 {
+		config.MixingFactor = 0.05
+	}
+	// without a positive kill support clusters are never removed, and every noise event would spawn a cluster
+	// living forever
+	if config.KillSupport == 0 {
+		config.KillSupport = 0.1
+	}
+
+	return &Tracker{config: config}, nil
+}
+
+func (c *Cluster) decay(ts, decayUs int) {
+	if ts > c.LastTs {
+		c.Support *= math.Exp(-float64(ts-c.LastTs) / float64(decayUs))
+		c.LastTs = ts
+	}
+}
+
+// Process assigns an event to a cluster and returns the records of the windows which ended before the event
+func (t *Tracker) Process(ev event.Event) []Record {
+	records := []Record{}
+	if !t.started {
+		t.started = true
+		t.nextWindow = ev.Ts + t.config.WindowUs
+	}
+	for ev.Ts >= t.nextWindow {
+		records = append(records, t.records(t.nextWindow)...)
+		t.nextWindow += t.config.WindowUs
+	}
+
+	// decay and remove clusters without support
+	alive := t.clusters[:0]
+	for _, c := range t.clusters {
+		c.decay(ev.Ts, t.config.DecayUs)
+		if c.Support >= t.config.KillSupport {
+			alive = append(alive, c)
+		}
+	}
+	t.clusters = alive
+
+	x, y := float64(ev.Coords.X), float64(ev.Coords.Y)
+	var nearest *Cluster
+	nearestDist := t.config.Radius
+	for _, c := range t.clusters {
+		if d := math.Hypot(c.X-x, c.Y-y); d <= nearestDist {
+			nearest, nearestDist = c, d
+		}
+	}
+
+	if nearest == nil {
+		if t.config.MaxClusters == 0 || len(t.clusters) < t.config.MaxClusters {
+			t.clusters = append(t.clusters, &Cluster{
+				ID: t.nextID, X: x, Y: y, Support: 1, LastTs: ev.Ts,
+				windowX: x, windowY: y, windowTs: ev.Ts,
+			})
+			t.nextID++
+		}
+		return records
+	}
+
+	m := t.config.MixingFactor
+	nearest.X += m * (x - nearest.X)
+	nearest.Y += m * (y - nearest.Y)
+	nearest.Size += m * (nearestDist - nearest.Size)
+	nearest.Support++
+
+	t.merge(nearest)
+	return records
+}
+
+// merge joins clusters closer than the radius to c into the cluster with the highest support
+func (t *Tracker) merge(c *Cluster) {
+	for i := 0; i < len(t.clusters); i++ {
+		o := t.clusters[i]
+		if o == c || math.Hypot(o.X-c.X, o.Y-c.Y) > t.config.Radius {
+			continue
+		}
+
+		keep, drop := c, o
+		if o.Support > c.Support {
+			keep, drop = o, c
+		}
+		total := keep.Support + drop.Support
+		keep.X = (keep.X*keep.Support + drop.X*drop.Support) / total
+		keep.Y = (keep.Y*keep.Support + drop.Y*drop.Support) / total
+		keep.Size = (keep.Size*keep.Support + drop.Size*drop.Support) / total
+		keep.Support = total
+
+		for j, d := range t.clusters {
+			if d == drop {
+				t.clusters = append(t.clusters[:j], t.clusters[j+1:]...)
+				break
+			}
+		}
+		c = keep
+		i = -1
+	}
+}
+
+// records reports the visible clusters at the end of a window
+func (t *Tracker) records(ts int) []Record {
+	records := []Record{}
+	for _, c := range t.clusters {
+		c.decay(ts, t.config.DecayUs)
+		if c.Support >= t.config.VisibleSupport {
+			r := Record{TrackID: c.ID, Ts: ts, X: c.X, Y: c.Y, Size: c.Size, Support: c.Support}
+			// clusters spawned during the window would report their displacement over a few microsseconds
+			if dt := float64(ts-c.windowTs) / 1e6; c.windowed && dt > 0 {
+				r.VX, r.VY = (c.X-c.windowX)/dt, (c.Y-c.windowY)/dt
+			}
+			records = append(records, r)
+		}
+		c.windowX, c.windowY, c.windowTs, c.windowed = c.X, c.Y, ts, true
+	}
+	return records
+}
+
+// Feed processes events and returns the records of the windows which ended
+func (t *Tracker) Feed(events []event.Event) []Record {
+	records := []Record{}
+	for _, ev := range events {
+		records = append(records, t.Process(ev)...)
+	}
+	return records
+}
+
+// Flush returns the records of the current window, which is considered ended
+func (t *Tracker) Flush() []Record {
+	if !t.started {
+		return []Record{}
+	}
+	records := t.records(t.nextWindow)
+	t.nextWindow += t.config.WindowUs
+	return records
+}
+
+// Clusters returns a copy of the current clusters, including clusters which are not visible yet
+func (t *Tracker) Clusters() []Cluster {
+	clusters := make([]Cluster, len(t.clusters))
+	for i, c := range t.clusters {
+		clusters[i] = *c
+	}
+	return clusters
+}
+
+// Stream processes the captures received from in and sends the records to out, until in is closed or ctx is
+// done. The records of the last window are sent when in is closed.
+func (t *Tracker) Stream(ctx context.Context, in <-chan event.EventCapture, out chan<- Record) error {
+	send := func(records []Record) error {
+		for _, r := range records {
+			select {
+			case out <- r:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case evCap, ok := <-in:
+			if !ok {
+				return send(t.Flush())
+			}
+			if err := send(t.Feed(evCap.Events)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Track processes all events and returns every track record, including the last window
+func Track(events []event.Event, config Config) ([]Record, error) {
+	t, err := NewTracker(config)
+	if err != nil {
+		return nil, err
+	}
+	return append(t.Feed(events), t.Flush()...), nil
+}
+
+// WriteCSV writes records as CSV with a header row
+func WriteCSV(w io.Writer, records []Record) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"trackId", "ts", "x", "y", "vx", "vy", "size", "support"}); err != nil {
+		return err
+	}
+
+	f := func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+	for _, r := range records {
+		if err := cw.Write([]string{
+			strconv.Itoa(r.TrackID), strconv.Itoa(r.Ts), f(r.X), f(r.Y), f(r.VX), f(r.VY), f(r.Size), f(r.Support),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes records as a JSON array
+func WriteJSON(w io.Writer, records []Record) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
@@ -0,0 +1,200 @@
+package tracking
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ffardo/go-event-vision"
+)
+
+// blobs creates the events of a 3x3 blob moving at vx pixels per second from (10, 20), firing every millisecond
+// for 500ms, and of a static blob at (80, 80) firing during the first 100ms only
+func blobs(vx float64) []event.Event {
+	ev := []event.Event{}
+	for ts := 0; ts < 500e3; ts += 1000 {
+		cx := 10 + int(math.Round(vx*float64(ts)/1e6))
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				ev = append(ev, event.Event{Coords: event.Point2D{X: cx + dx, Y: 20 + dy}, Ts: ts, P: 1})
+				if ts < 100e3 {
+					ev = append(ev, event.Event{Coords: event.Point2D{X: 80 + dx, Y: 80 + dy}, Ts: ts, P: 0})
+				}
+			}
+		}
+	}
+	return ev
+}
+
+var testConfig = Config{
+	Radius:         6,
+	DecayUs:        20000,
+	VisibleSupport: 20,
+	KillSupport:    0.5,
+	WindowUs:       50000,
+}
+
+func TestNewTracker(t *testing.T) {
+	if _, err := NewTracker(Config{Radius: 5, DecayUs: 1000}); err == nil {
+		t.Errorf("NewTracker() accepted a configuration without window")
+	}
+	if _, err := NewTracker(testConfig); err != nil {
+		t.Errorf("NewTracker() error = %v", err)
+	}
+}
+
+func TestTrack(t *testing.T) {
+	records, err := Track(blobs(100), testConfig)
+	if err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+
+	moving, static := map[int]bool{}, map[int]bool{}
+	for _, r := range records {
+		if r.Y < 50 {
+			moving[r.TrackID] = true
+			// the velocity is reported from the second window, once the cluster converged
+			if r.Ts == 50e3 && (r.VX != 0 || r.VY != 0) {
+				t.Errorf("Track() velocity = %v, %v in the first window, want 0, 0", r.VX, r.VY)
+			}
+			if r.Ts > 100e3 && (math.Abs(r.VX-100) > 10 || math.Abs(r.VY) > 1) {
+				t.Errorf("Track() velocity = %v, %v at %v, want 100, 0", r.VX, r.VY, r.Ts)
+			}
+			if r.Size <= 0 || r.Size > 2 {
+				t.Errorf("Track() size = %v at %v", r.Size, r.Ts)
+			}
+		} else {
+			static[r.TrackID] = true
+			if r.Ts > 200e3 {
+				t.Errorf("Track() reported the static blob at %v after it stopped firing", r.Ts)
+			}
+		}
+	}
+
+	if len(moving) != 1 || len(static) != 1 {
+		t.Errorf("Track() tracks = %v moving, %v static, want 1 each", moving, static)
+	}
+	if last := records[len(records)-1]; last.Ts != 500e3 || math.Abs(last.X-59) > 3 {
+		t.Errorf("Track() last record = %+v", last)
+	}
+}
+
+func TestTrack_LateCluster(t *testing.T) {
+	// a static blob appearing 10us before the end of the first window
+	events := []event.Event{{Coords: event.Point2D{X: 0, Y: 0}, Ts: 0}}
+	for ts := 49990; ts < 150e3; ts += 1 {
+		if ts >= 50e3 && ts%1000 != 0 {
+			continue
+		}
+		for i := 0; i < 9; i++ {
+			events = append(events, event.Event{Coords: event.Point2D{X: 39 + i%3, Y: 39 + i/3}, Ts: ts})
+		}
+	}
+
+	records, err := Track(events, testConfig)
+	if err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+	if len(records) == 0 || records[0].Ts != 50e3 {
+		t.Fatalf("Track() = %v, want a record at the end of the first window", records)
+	}
+	for _, r := range records {
+		if (r.Ts == 50e3 && (r.VX != 0 || r.VY != 0)) || math.Abs(r.VX) > 20 || math.Abs(r.VY) > 20 {
+			t.Errorf("Track() velocity = %v, %v at %v for a static blob", r.VX, r.VY, r.Ts)
+		}
+	}
+}
+
+func TestTracker_Process(t *testing.T) {
+	tr, _ := NewTracker(Config{Radius: 4, DecayUs: 1000, WindowUs: 1000, MaxClusters: 1})
+	tr.Process(event.Event{Coords: event.Point2D{X: 5, Y: 5}, Ts: 0})
+	tr.Process(event.Event{Coords: event.Point2D{X: 50, Y: 50}, Ts: 10})
+	tr.Process(event.Event{Coords: event.Point2D{X: 7, Y: 5}, Ts: 20})
+
+	clusters := tr.Clusters()
+	if len(clusters) != 1 {
+		t.Fatalf("Tracker.Clusters() = %v, want a single cluster", clusters)
+	}
+	if c := clusters[0]; c.ID != 0 || c.X != 5.1 || c.Size != 0.1 || c.LastTs != 20 {
+		t.Errorf("Tracker.Clusters() = %+v", c)
+	}
+}
+
+func TestTracker_KillSupport(t *testing.T) {
+	if _, err := NewTracker(Config{Radius: 4, DecayUs: 1000, WindowUs: 1000, KillSupport: -1}); err == nil {
+		t.Errorf("NewTracker() accepted a negative kill support")
+	}
+
+	// clusters are removed with the default kill support
+	tr, _ := NewTracker(Config{Radius: 4, DecayUs: 1000, WindowUs: 1000})
+	tr.Process(event.Event{Coords: event.Point2D{X: 5, Y: 5}, Ts: 0})
+	tr.Process(event.Event{Coords: event.Point2D{X: 50, Y: 50}, Ts: 10000})
+
+	if clusters := tr.Clusters(); len(clusters) != 1 || clusters[0].ID != 1 {
+		t.Errorf("Tracker.Clusters() = %+v, want the last cluster only", clusters)
+	}
+}
+
+func TestTracker_Stream(t *testing.T) {
+	events := blobs(100)
+	want, _ := Track(events, testConfig)
+
+	in := make(chan event.EventCapture)
+	out := make(chan Record)
+	tr, _ := NewTracker(testConfig)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- tr.Stream(context.Background(), in, out)
+		close(out)
+	}()
+	go func() {
+		for i := 0; i < len(events); i += 1000 {
+			end := i + 1000
+			if end > len(events) {
+				end = len(events)
+			}
+			in <- event.EventCapture{Events: events[i:end]}
+		}
+		close(in)
+	}()
+
+	got := []Record{}
+	for r := range out {
+		got = append(got, r)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("Tracker.Stream() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tracker.Stream() returned %v records, want %v", len(got), len(want))
+	}
+}
+
+func TestWrite(t *testing.T) {
+	records := []Record{{TrackID: 3, Ts: 1000, X: 1.5, Y: 2, VX: -10, VY: 0.25, Size: 1, Support: 30}}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, records); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	want := "trackId,ts,x,y,vx,vy,size,support\n3,1000,1.5,2,-10,0.25,1,30\n"
+	if buf.String() != want {
+		t.Errorf("WriteCSV() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := WriteJSON(&buf, records); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	got := []Record{}
+	if err := json.NewDecoder(strings.NewReader(buf.String())).Decode(&got); err != nil {
+		t.Fatalf("WriteJSON() output is invalid: %v", err)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Errorf("WriteJSON() = %v, want %v", got, records)
+	}
+}